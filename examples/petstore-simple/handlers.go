@@ -3,14 +3,27 @@ package example
 // Code generated by "fiberopenapi -spec ./petstore-simple.json"; DO NOT EDIT.
 
 import (
-	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // Implement this interface.
 type Handlers interface {
+	// Find a pet
+	//
+	// This operation will find a pet in the database.
+	//
+	// Tags: pet.
 	FindPet(c *fiber.Ctx, id FindPetId) error
+
+	// Update a pet
+	//
+	// This operation will update a pet in the database.
+	//
+	// Tags: pet. Security: apiKey.
 	UpdatePet(c *fiber.Ctx, id UpdatePetId) error
 }
 
@@ -24,26 +37,230 @@ func addRawHandlers(app *fiber.App, h rawHandlers) {
 	app.Put("/pet/:id", h.UpdatePet)
 }
 
+// An operation of the specification.
+type HandlersOperation struct {
+	// The operationId of the specification.
+	ID string
+	// The HTTP method, like GET.
+	Method string
+	// The path as registered in Fiber, like /pets/:id, and as written in the
+	// specification, like /pets/{id}.
+	Path         string
+	PathTemplate string
+	Tags         []string
+	// The alternative security requirements, each one with the scopes of its
+	// schemes. An empty requirement makes the security optional.
+	Security   []map[string][]string
+	Deprecated bool
+	// The status codes of the declared responses, like 200, 4XX or default.
+	Responses []string
+	// Matches the paths of the requests of the operation.
+	pattern *regexp.Regexp
+}
+
+// The operations of the specification, in the order in which they are
+// registered.
+var HandlersOperations = []*HandlersOperation{
+	{
+		ID:           "find-pet",
+		Method:       "GET",
+		Path:         "/pet/:id",
+		PathTemplate: "/pet/{id}",
+		Tags:         []string{"pet"},
+		Security:     nil,
+		Deprecated:   false,
+		Responses:    []string{"400"},
+		pattern:      regexp.MustCompile("^/pet/[^/]+$"),
+	},
+	{
+		ID:           "update-pet",
+		Method:       "PUT",
+		Path:         "/pet/:id",
+		PathTemplate: "/pet/{id}",
+		Tags:         []string{"pet"},
+		Security:     []map[string][]string{{"apiKey": nil}},
+		Deprecated:   false,
+		Responses:    []string{"400"},
+		pattern:      regexp.MustCompile("^/pet/[^/]+$"),
+	},
+}
+
+// LookupHandlersOperation returns the operation of a method and a request path,
+// like /pets/42, or a route, like /pets/:id. Middlewares that run before the
+// handlers can use it with c.Method() and c.Path().
+func LookupHandlersOperation(method, path string) (*HandlersOperation, bool) {
+	for _, operation := range HandlersOperations {
+		if operation.Method == method && operation.pattern.MatchString(path) {
+			return operation, true
+		}
+	}
+	return nil, false
+}
+
+type handlersOperationKey struct{}
+
+// HandlersOperationOf returns the operation of a request, which the handlers store
+// in its locals. Middlewares registered before the handlers can use it after
+// calling c.Next(), to log or measure requests by operation ID for example.
+func HandlersOperationOf(c *fiber.Ctx) (*HandlersOperation, bool) {
+	operation, ok := c.Locals(handlersOperationKey{}).(*HandlersOperation)
+	return operation, ok
+}
+
 type validatedHandlers struct {
 	validated Handlers
+	// Whether the bodies of the responses are validated, and what to do with
+	// the ones that are not valid.
+	validateResponses bool
+	onInvalidResponse func(c *fiber.Ctx, err error) error
+	// Called when a request uses a deprecated parameter.
+	onDeprecatedParameter func(c *fiber.Ctx, operation, parameter string)
+	// Called around each request, in order.
+	observers []HandlersObserver
 }
 
-func AddHandlers(app *fiber.App, h Handlers) {
-	addRawHandlers(app, &validatedHandlers{h})
+// An option of AddHandlers.
+type HandlersOption func(*validatedHandlers)
+
+// WithHandlersResponseValidation validates the bodies of the responses against
+// the schemas of the specification, to catch contract bugs in development.
+// When a body is not valid, the result of onInvalid is returned, which can log
+// the error for example. If onInvalid is nil, the request fails with 500
+// Internal Server Error instead.
+func WithHandlersResponseValidation(onInvalid func(c *fiber.Ctx, err error) error) HandlersOption {
+	return func(h *validatedHandlers) {
+		h.validateResponses = true
+		h.onInvalidResponse = onInvalid
+	}
 }
 
-func (h *validatedHandlers) FindPet(c *fiber.Ctx) error {
-	var id FindPetId
-	if err := json.Unmarshal([]byte(c.Params("id")), &id); err != nil {
-		return err
+// WithHandlersDeprecatedParameterHook calls hook when a request uses a
+// deprecated parameter, with the names of the operation and of the parameter,
+// for example to log it.
+func WithHandlersDeprecatedParameterHook(hook func(c *fiber.Ctx, operation, parameter string)) HandlersOption {
+	return func(h *validatedHandlers) {
+		h.onDeprecatedParameter = hook
 	}
-	return h.validated.FindPet(c, id)
 }
 
-func (h *validatedHandlers) UpdatePet(c *fiber.Ctx) error {
-	var id UpdatePetId
-	if err := json.Unmarshal([]byte(c.Params("id")), &id); err != nil {
-		return err
+// Observes the requests of each operation, to trace them or to measure their
+// latency and validation failures for example, without changing the handlers.
+type HandlersObserver interface {
+	// OnRequest is called when a request is received, before it is decoded.
+	OnRequest(c *fiber.Ctx, operation *HandlersOperation)
+	// OnValidationError is called when a request is not valid, with the
+	// error that is returned instead of calling the handler.
+	OnValidationError(c *fiber.Ctx, operation *HandlersOperation, err error)
+	// OnResponse is called when a request is done, with the status of its
+	// response, or of the error returned, and how long it took.
+	OnResponse(c *fiber.Ctx, operation *HandlersOperation, status int, duration time.Duration)
+}
+
+// HandlersObserverFuncs adapts functions to a HandlersObserver, for example to
+// start and end spans or to record metrics. Nil functions are not called.
+type HandlersObserverFuncs struct {
+	Request         func(c *fiber.Ctx, operation *HandlersOperation)
+	ValidationError func(c *fiber.Ctx, operation *HandlersOperation, err error)
+	Response        func(c *fiber.Ctx, operation *HandlersOperation, status int, duration time.Duration)
+}
+
+func (o HandlersObserverFuncs) OnRequest(c *fiber.Ctx, operation *HandlersOperation) {
+	if o.Request != nil {
+		o.Request(c, operation)
+	}
+}
+
+func (o HandlersObserverFuncs) OnValidationError(c *fiber.Ctx, operation *HandlersOperation, err error) {
+	if o.ValidationError != nil {
+		o.ValidationError(c, operation, err)
+	}
+}
+
+func (o HandlersObserverFuncs) OnResponse(c *fiber.Ctx, operation *HandlersOperation, status int, duration time.Duration) {
+	if o.Response != nil {
+		o.Response(c, operation, status, duration)
 	}
-	return h.validated.UpdatePet(c, id)
+}
+
+// WithHandlersObserver calls observer around each request. Observers are called
+// in the order in which they are added.
+func WithHandlersObserver(observer HandlersObserver) HandlersOption {
+	return func(h *validatedHandlers) {
+		h.observers = append(h.observers, observer)
+	}
+}
+
+func AddHandlers(app *fiber.App, h Handlers, options ...HandlersOption) {
+	validated := &validatedHandlers{validated: h}
+	for _, option := range options {
+		option(validated)
+	}
+	addRawHandlers(app, validated)
+}
+
+// Stores the operation in the locals of the request and calls the observers
+// around handle.
+func (h *validatedHandlers) observe(c *fiber.Ctx, operation *HandlersOperation, handle func() error) error {
+	c.Locals(handlersOperationKey{}, operation)
+	if len(h.observers) == 0 {
+		return handle()
+	}
+	start := time.Now()
+	for _, observer := range h.observers {
+		observer.OnRequest(c, operation)
+	}
+	err := handle()
+	duration := time.Since(start)
+	// The error handler of Fiber has not set the status of errors yet.
+	status := c.Response().StatusCode()
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		status = fiberErr.Code
+	} else if err != nil {
+		status = fiber.StatusInternalServerError
+	}
+	for _, observer := range h.observers {
+		observer.OnResponse(c, operation, status, duration)
+	}
+	return err
+}
+
+// Reports a request that is not valid to the observers.
+func (h *validatedHandlers) invalidRequest(c *fiber.Ctx, err error) error {
+	if operation, ok := HandlersOperationOf(c); ok {
+		for _, observer := range h.observers {
+			observer.OnValidationError(c, operation, err)
+		}
+	}
+	return err
+}
+
+func (h *validatedHandlers) invalidResponse(c *fiber.Ctx, err error) error {
+	if err == nil {
+		return nil
+	}
+	if h.onInvalidResponse != nil {
+		return h.onInvalidResponse(c, err)
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
+
+func (h *validatedHandlers) FindPet(c *fiber.Ctx) error {
+	return h.observe(c, HandlersOperations[0], func() error {
+		var id FindPetId
+		if err := decodeParameter("id", c.Params("id"), true, &id); err != nil {
+			return h.invalidRequest(c, err)
+		}
+		return h.validated.FindPet(c, id)
+	})
+}
+
+func (h *validatedHandlers) UpdatePet(c *fiber.Ctx) error {
+	return h.observe(c, HandlersOperations[1], func() error {
+		var id UpdatePetId
+		if err := decodeParameter("id", c.Params("id"), true, &id); err != nil {
+			return h.invalidRequest(c, err)
+		}
+		return h.validated.UpdatePet(c, id)
+	})
 }
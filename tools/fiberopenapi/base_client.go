@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HTTPDoer sends HTTP requests, like *http.Client does.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HTTPDoerFunc adapts a function to an HTTPDoer.
+type HTTPDoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f HTTPDoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps how requests are sent, for example to authenticate, log or
+// retry them.
+type Middleware func(next HTTPDoer) HTTPDoer
+
+// Wraps a doer with middlewares, the first one being the outermost.
+func chainMiddlewares(doer HTTPDoer, middlewares []Middleware) HTTPDoer {
+	for _, middleware := range slices.Backward(middlewares) {
+		doer = middleware(doer)
+	}
+	return doer
+}
+
+// Builds a request with a JSON body, unless body is nil.
+func newRequest(ctx context.Context, method, target string, body any) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// Sends a request and reads the body of its response.
+func sendRequest(doer HTTPDoer, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+	return resp, data, nil
+}
+
+// Decodes the body of a response as the model of its status code, if it has
+// one.
+func decodeResponse[T any](data []byte) (*T, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %w", err)
+	}
+	return v, nil
+}
+
+// The value of a parameter as a primitive, a slice or a map, decoded from its
+// JSON encoding so that it can be serialized with any style.
+func parameterValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// The text of a primitive value. Nested arrays and objects are encoded as JSON.
+func primitiveText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// The items of an array, or the keys and values of an object, sorted by key,
+// as text.
+func parameterItems(value any) (items []string, object bool) {
+	switch value := value.(type) {
+	case []any:
+		for _, item := range value {
+			items = append(items, primitiveText(item))
+		}
+		return items, false
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			items = append(items, key, primitiveText(value[key]))
+		}
+		return items, true
+	}
+	return []string{primitiveText(value)}, false
+}
+
+// Joins the keys and values of an object as key=value pairs.
+func joinPairs(items []string, separator string, escape func(string) string) string {
+	pairs := make([]string, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		pairs = append(pairs, escape(items[i])+"="+escape(items[i+1]))
+	}
+	return strings.Join(pairs, separator)
+}
+
+func joinEscaped(items []string, separator string, escape func(string) string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = escape(item)
+	}
+	return strings.Join(escaped, separator)
+}
+
+func noEscape(s string) string {
+	return s
+}
+
+// Serializes a path parameter with the simple, label or matrix style, see
+// https://spec.openapis.org/oas/v3.1.0#style-values.
+func pathParameter(name, style string, explode bool, v any) (string, error) {
+	value, err := parameterValue(v)
+	if err != nil {
+		return "", err
+	}
+	items, object := parameterItems(value)
+	escape := url.PathEscape
+	switch style {
+	case "label":
+		switch {
+		case object && explode:
+			return "." + joinPairs(items, ".", escape), nil
+		case explode:
+			return "." + joinEscaped(items, ".", escape), nil
+		}
+		return "." + joinEscaped(items, ",", escape), nil
+	case "matrix":
+		switch {
+		case object && explode:
+			return ";" + joinPairs(items, ";", escape), nil
+		case explode:
+			pairs := make([]string, len(items))
+			for i, item := range items {
+				pairs[i] = escape(name) + "=" + escape(item)
+			}
+			return ";" + strings.Join(pairs, ";"), nil
+		}
+		return ";" + escape(name) + "=" + joinEscaped(items, ",", escape), nil
+	}
+	if object && explode {
+		return joinPairs(items, ",", escape), nil
+	}
+	return joinEscaped(items, ",", escape), nil
+}
+
+// Adds a query parameter with the form, spaceDelimited, pipeDelimited or
+// deepObject style.
+func addQueryParameter(query url.Values, name, style string, explode bool, v any) error {
+	value, err := parameterValue(v)
+	if err != nil {
+		return err
+	}
+	items, object := parameterItems(value)
+	switch {
+	case style == "deepObject" && object:
+		for i := 0; i+1 < len(items); i += 2 {
+			query.Add(fmt.Sprintf("%s[%s]", name, items[i]), items[i+1])
+		}
+	case style == "spaceDelimited" && !explode:
+		query.Add(name, strings.Join(items, " "))
+	case style == "pipeDelimited" && !explode:
+		query.Add(name, strings.Join(items, "|"))
+	case object && explode:
+		for i := 0; i+1 < len(items); i += 2 {
+			query.Add(items[i], items[i+1])
+		}
+	case explode:
+		for _, item := range items {
+			query.Add(name, item)
+		}
+	default:
+		query.Add(name, strings.Join(items, ","))
+	}
+	return nil
+}
+
+// Serializes a header parameter with the simple style.
+func headerParameter(explode bool, v any) (string, error) {
+	value, err := parameterValue(v)
+	if err != nil {
+		return "", err
+	}
+	items, object := parameterItems(value)
+	if object && explode {
+		return joinPairs(items, ",", noEscape), nil
+	}
+	return strings.Join(items, ","), nil
+}
+
+// Serializes a cookie parameter with the form style.
+func cookieParameter(name string, v any) (*http.Cookie, error) {
+	value, err := parameterValue(v)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := parameterItems(value)
+	return &http.Cookie{Name: name, Value: strings.Join(items, ",")}, nil
+}
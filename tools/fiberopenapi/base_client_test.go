@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathParameter(t *testing.T) {
+	array := []string{"3", "4 5"}
+	object := map[string]any{"role": "admin", "first": "Alex"}
+	testCases := map[string]struct {
+		style    string
+		explode  bool
+		value    any
+		expected string
+	}{
+		"simple primitive": {style: "simple", value: "a/b", expected: "a%2Fb"},
+		"simple array":     {style: "simple", value: array, expected: "3,4%205"},
+		"simple object":    {style: "simple", value: object, expected: "first,Alex,role,admin"},
+		"simple exploded":  {style: "simple", explode: true, value: object, expected: "first=Alex,role=admin"},
+		"label array":      {style: "label", value: array, expected: ".3,4%205"},
+		"label exploded":   {style: "label", explode: true, value: array, expected: ".3.4%205"},
+		"matrix primitive": {style: "matrix", value: 5, expected: ";id=5"},
+		"matrix array":     {style: "matrix", value: array, expected: ";id=3,4%205"},
+		"matrix exploded":  {style: "matrix", explode: true, value: array, expected: ";id=3;id=4%205"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			segment, err := pathParameter("id", tc.style, tc.explode, tc.value)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, segment)
+		})
+	}
+}
+
+func TestQueryParameter(t *testing.T) {
+	array := []int{3, 4}
+	object := map[string]any{"role": "admin", "first": "Alex"}
+	testCases := map[string]struct {
+		style    string
+		explode  bool
+		value    any
+		expected url.Values
+	}{
+		"form primitive":  {style: "form", explode: true, value: true, expected: url.Values{"id": {"true"}}},
+		"form exploded":   {style: "form", explode: true, value: array, expected: url.Values{"id": {"3", "4"}}},
+		"form array":      {style: "form", value: array, expected: url.Values{"id": {"3,4"}}},
+		"form object":     {style: "form", explode: true, value: object, expected: url.Values{"first": {"Alex"}, "role": {"admin"}}},
+		"space delimited": {style: "spaceDelimited", value: array, expected: url.Values{"id": {"3 4"}}},
+		"pipe delimited":  {style: "pipeDelimited", value: array, expected: url.Values{"id": {"3|4"}}},
+		"deep object":     {style: "deepObject", explode: true, value: object, expected: url.Values{"id[first]": {"Alex"}, "id[role]": {"admin"}}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			query := url.Values{}
+			require.NoError(t, addQueryParameter(query, "id", tc.style, tc.explode, tc.value))
+			assert.Equal(t, tc.expected, query)
+		})
+	}
+}
+
+func TestHeaderAndCookieParameters(t *testing.T) {
+	header, err := headerParameter(true, map[string]any{"a": 1, "b": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, "a=1,b=x", header)
+
+	cookie, err := cookieParameter("session", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "session", cookie.Name)
+	assert.Equal(t, "a,b", cookie.Value)
+}
+
+// A model with a writeOnly property, like the generated ones.
+type account struct {
+	Name     string  `json:"name"`
+	Password *string `json:"password,omitempty"`
+}
+
+func (m *account) OmitWriteOnly() {
+	m.Password = nil
+}
+
+func TestWriteOnlyRoundTrip(t *testing.T) {
+	// The server echoes the account, as the generated handlers do with the
+	// bodies of their responses.
+	var received account
+	app := fiber.New()
+	app.Post("/accounts", func(c *fiber.Ctx) error {
+		if err := json.Unmarshal(c.Body(), &received); err != nil {
+			return err
+		}
+		if err := c.JSON(received); err != nil {
+			return err
+		}
+		omitWriteOnly[account](c.Response())
+		return nil
+	})
+	doer := HTTPDoerFunc(func(req *http.Request) (*http.Response, error) {
+		return app.Test(req, -1)
+	})
+
+	password := "secret"
+	req, err := newRequest(context.Background(), http.MethodPost, "http://localhost/accounts",
+		account{Name: "Alex", Password: &password},
+	)
+	require.NoError(t, err)
+	resp, data, err := sendRequest(doer, req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// Clients send writeOnly properties, but servers never send them back.
+	assert.Equal(t, account{Name: "Alex", Password: &password}, received)
+	assert.JSONEq(t, `{"name": "Alex"}`, string(data))
+}
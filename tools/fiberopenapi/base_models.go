@@ -1,15 +1,54 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
 )
 
 var (
-	ErrMaxLength = errors.New("maxLength")
-	ErrMinLength = errors.New("minLength")
+	ErrMaxLength  = errors.New("maxLength")
+	ErrMinLength  = errors.New("minLength")
+	ErrReadOnly   = errors.New("readOnly")
+	ErrRequired   = errors.New("required")
+	ErrMaxDepth   = errors.New("maxDepth")
+	ErrType       = errors.New("type")
+	ErrFormat     = errors.New("format")
+	ErrConst      = errors.New("const")
+	ErrItems      = errors.New("items")
+	ErrMinItems   = errors.New("minItems")
+	ErrMaxItems   = errors.New("maxItems")
+	ErrEnum       = errors.New("enum")
+	ErrPattern    = errors.New("pattern")
+	ErrMinimum    = errors.New("minimum")
+	ErrMaximum    = errors.New("maximum")
+	ErrMultipleOf = errors.New("multipleOf")
+
+	ErrExclusiveMinimum = errors.New("exclusiveMinimum")
+	ErrExclusiveMaximum = errors.New("exclusiveMaximum")
+	ErrUniqueItems      = errors.New("uniqueItems")
+	ErrMinProperties    = errors.New("minProperties")
+	ErrMaxProperties    = errors.New("maxProperties")
+
+	ErrMinContains           = errors.New("minContains")
+	ErrMaxContains           = errors.New("maxContains")
+	ErrDependentRequired     = errors.New("dependentRequired")
+	ErrDependentSchemas      = errors.New("dependentSchemas")
+	ErrThen                  = errors.New("then")
+	ErrElse                  = errors.New("else")
+	ErrUnevaluatedProperties = errors.New("unevaluatedProperties")
 )
 
+// Maximum nesting depth of the JSON values accepted in request bodies.
+// Recursive schemas accept arbitrarily nested values, so deeper ones are
+// rejected before being unmarshalled and validated.
+var MaxDepth = 64
+
 type ValidationError struct {
 	Err error
 }
@@ -37,8 +76,367 @@ func NewMinLengthError(got int, want int) error {
 	return NewValidationError("%w: got %d, want %d", ErrMinLength, got, want)
 }
 
+func NewMaxDepthError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMaxDepth, got, want)
+}
+
+func NewTypeError(got []byte, want string) error {
+	return NewValidationError("%w: got %s, want %s", ErrType, got, want)
+}
+
+func NewFormatError(got string, want string) error {
+	return NewValidationError("%w: got %s, want %s", ErrFormat, got, want)
+}
+
+func NewConstError(got []byte, want string) error {
+	return NewValidationError("%w: got %s, want %s", ErrConst, got, want)
+}
+
+func NewItemsError(got int, want int) error {
+	return NewValidationError("%w: got %d, want at most %d", ErrItems, got, want)
+}
+
+func NewMinItemsError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMinItems, got, want)
+}
+
+func NewMaxItemsError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMaxItems, got, want)
+}
+
+func NewEnumError(got any) error {
+	return NewValidationError("%w: got %v", ErrEnum, got)
+}
+
+func NewPatternError(got string, pattern string) error {
+	return NewValidationError("%w: got %q, want %s", ErrPattern, got, pattern)
+}
+
+func NewMinimumError(got float64, want float64) error {
+	return NewValidationError("%w: got %v, want %v", ErrMinimum, got, want)
+}
+
+func NewMaximumError(got float64, want float64) error {
+	return NewValidationError("%w: got %v, want %v", ErrMaximum, got, want)
+}
+
+func NewExclusiveMinimumError(got float64, want float64) error {
+	return NewValidationError("%w: got %v, want %v", ErrExclusiveMinimum, got, want)
+}
+
+func NewExclusiveMaximumError(got float64, want float64) error {
+	return NewValidationError("%w: got %v, want %v", ErrExclusiveMaximum, got, want)
+}
+
+func NewMultipleOfError(got float64, want float64) error {
+	return NewValidationError("%w: got %v, want %v", ErrMultipleOf, got, want)
+}
+
+func NewUniqueItemsError(got int, want int) error {
+	return NewValidationError("%w: items %d and %d are equal", ErrUniqueItems, got, want)
+}
+
+func NewMinPropertiesError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMinProperties, got, want)
+}
+
+func NewMaxPropertiesError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMaxProperties, got, want)
+}
+
+func NewMinContainsError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMinContains, got, want)
+}
+
+func NewMaxContainsError(got int, want int) error {
+	return NewValidationError("%w: got %d, want %d", ErrMaxContains, got, want)
+}
+
+func NewDependentRequiredError(property string, dependency string) error {
+	return NewValidationError("%w: %s requires %s", ErrDependentRequired, property, dependency)
+}
+
+func NewDependentSchemasError(property string) error {
+	return NewValidationError("%w: %s", ErrDependentSchemas, property)
+}
+
+func NewThenError() error {
+	return NewValidationError("%w", ErrThen)
+}
+
+func NewElseError() error {
+	return NewValidationError("%w", ErrElse)
+}
+
+func NewUnevaluatedPropertyError(property string) error {
+	return NewValidationError("%w: %s", ErrUnevaluatedProperties, property)
+}
+
+func NewReadOnlyError(property string) error {
+	return NewValidationError("%w: %s", ErrReadOnly, property)
+}
+
+func NewRequiredError(property string) error {
+	return NewValidationError("%w: %s", ErrRequired, property)
+}
+
+// Implemented by models that have readOnly or required writeOnly properties.
+type requestValidator interface {
+	ValidateRequest() error
+}
+
+// Rejects readOnly properties in a request body, and requires the required
+// writeOnly ones.
+func validateRequest(v any) error {
+	if r, ok := v.(requestValidator); ok {
+		return r.ValidateRequest()
+	}
+	return nil
+}
+
+// Implemented by all the models, except the existing Go types set with the
+// x-go-type extension.
+type validator interface {
+	Validate() error
+}
+
+// Checks the constraints of the specification on a model.
+func validate(v any) error {
+	if v, ok := v.(validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// Decodes the value of a parameter and checks it. Values are JSON, like 42 or
+// true, or plain strings, like cat, which are not quoted. Parameters that are
+// not sent keep their zero value, unless they are required.
+func decodeParameter(name, value string, required bool, v any) error {
+	if value == "" {
+		if required {
+			return NewRequiredError(name)
+		}
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		quoted, _ := json.Marshal(value)
+		if json.Unmarshal(quoted, v) != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if err := validate(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Decodes a response body as the model T and checks it.
+func validateResponse[T any](body []byte) error {
+	var v T
+	if err := json.Unmarshal(body, &v); err != nil {
+		return err
+	}
+	return validate(&v)
+}
+
+// Implemented by models that have writeOnly properties.
+type writeOnlyOmitter interface {
+	OmitWriteOnly()
+}
+
+// The response of a request, like *fasthttp.Response, whose body can be
+// replaced.
+type responseBody interface {
+	Body() []byte
+	SetBody(body []byte)
+}
+
+// Removes the writeOnly properties of the model T from the body of a response,
+// which servers must never send. Request bodies keep them, as that is where
+// clients send them. Only the properties that T omits are removed, so that the
+// rest of the body, with the properties that T does not declare, is sent as
+// is. Bodies that do not decode as T are left unchanged, for the validation
+// of the responses to report them.
+func omitWriteOnly[T any](response responseBody) {
+	if len(response.Body()) == 0 {
+		return
+	}
+	var v T
+	if json.Unmarshal(response.Body(), &v) != nil {
+		return
+	}
+	o, ok := any(&v).(writeOnlyOmitter)
+	if !ok {
+		return
+	}
+	before, err := jsonTree(v)
+	if err != nil {
+		return
+	}
+	o.OmitWriteOnly()
+	after, err := jsonTree(v)
+	if err != nil {
+		return
+	}
+	body, err := jsonTree(json.RawMessage(response.Body()))
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(removeOmitted(body, before, after))
+	if err != nil {
+		return
+	}
+	response.SetBody(data)
+}
+
+// Encodes a value as the generic JSON value that it decodes as, with its
+// numbers as written.
+func jsonTree(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree any
+	err = decoder.Decode(&tree)
+	return tree, err
+}
+
+// Removes from a JSON value the properties that its model encoded before
+// omitting its writeOnly properties, but not after.
+func removeOmitted(value, before, after any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		beforeObject, _ := before.(map[string]any)
+		afterObject, _ := after.(map[string]any)
+		for key, property := range typed {
+			if _, ok := beforeObject[key]; !ok {
+				continue
+			}
+			if _, ok := afterObject[key]; !ok {
+				delete(typed, key)
+				continue
+			}
+			typed[key] = removeOmitted(property, beforeObject[key], afterObject[key])
+		}
+	case []any:
+		beforeArray, _ := before.([]any)
+		afterArray, _ := after.([]any)
+		if len(beforeArray) != len(typed) || len(afterArray) != len(typed) {
+			break
+		}
+		for i, item := range typed {
+			typed[i] = removeOmitted(item, beforeArray[i], afterArray[i])
+		}
+	}
+	return value
+}
+
+// Decodes the default value of a property, given by the specification, into
+// its field. The types of the defaults are checked by the generation, and the
+// ones that the field still rejects, like by its const or if keywords, leave
+// it unset.
+func decodeDefault[T any](data string, v *T) {
+	var value T
+	if json.Unmarshal([]byte(data), &value) == nil {
+		*v = value
+	}
+}
+
+// Whether a number is a multiple of another, as much as float64 allows.
+func isMultipleOf(n float64, divisor float64) bool {
+	q := n / divisor
+	return math.Abs(q-math.Round(q)) < 1e-9
+}
+
+// Compares a rational number with a number of the specification, like "0.01",
+// exactly.
+func cmpRat(r *big.Rat, number string) int {
+	n, _ := new(big.Rat).SetString(number)
+	return r.Cmp(n)
+}
+
+// Whether a rational number is an exact multiple of a number of the
+// specification.
+func isRatMultipleOf(r *big.Rat, divisor string) bool {
+	d, _ := new(big.Rat).SetString(divisor)
+	return new(big.Rat).Quo(r, d).IsInt()
+}
+
+// The indexes of the first two items that are equal, if any, comparing their
+// JSON encoding.
+func duplicateItems[T any](items []T) (int, int, bool) {
+	seen := map[string]int{}
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		key, _ := RawValue(data).normalize()
+		if j, ok := seen[key]; ok {
+			return j, i, true
+		}
+		seen[key] = i
+	}
+	return 0, 0, false
+}
+
+// Rejects JSON values nested deeper than MaxDepth.
+func checkDepth(data []byte) error {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > MaxDepth {
+				return NewMaxDepthError(depth, MaxDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// The JSON null value, the model of the null type.
 type Null struct{}
 
+func (Null) Validate() error {
+	return nil
+}
+
+func (Null) IsNull() bool {
+	return true
+}
+
+func (Null) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON only accepts null, unlike the empty struct that accepts any
+// object.
+func (*Null) UnmarshalJSON(data []byte) error {
+	if string(data) != "null" {
+		return NewTypeError(data, "null")
+	}
+	return nil
+}
+
 type nullable interface {
 	IsNull() bool
 }
@@ -52,3 +450,268 @@ func IsNull(v any) bool {
 	}
 	return false
 }
+
+// A JSON value of any type, the model of free-form schemas. The accessors
+// decode the value as each of the types, if it is of that type.
+type RawValue json.RawMessage
+
+func (v RawValue) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("null"), nil
+	}
+	return v, nil
+}
+
+func (v *RawValue) UnmarshalJSON(data []byte) error {
+	*v = append((*v)[0:0], data...)
+	return nil
+}
+
+func (v RawValue) Validate() error {
+	return nil
+}
+
+func (v RawValue) IsNull() bool {
+	return v == nil || string(v) == "null"
+}
+
+// The JSON type of the value: null, boolean, object, array, number or string.
+func (v RawValue) Type() string {
+	for _, c := range v {
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case 'n':
+			return "null"
+		case 't', 'f':
+			return "boolean"
+		case '{':
+			return "object"
+		case '[':
+			return "array"
+		case '"':
+			return "string"
+		}
+		return "number"
+	}
+	return "null"
+}
+
+// Is reports whether the value is of any of the types, which are the JSON
+// types plus integer, the numbers without a fractional part.
+func (v RawValue) Is(types ...string) bool {
+	for _, t := range types {
+		if t == v.Type() {
+			return true
+		}
+		if n, ok := v.Number(); ok && t == "integer" && n == float64(int64(n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal reports whether the value is equal to a JSON value, regardless of
+// whitespace, the order of the properties and the representation of numbers.
+func (v RawValue) Equal(value string) bool {
+	a, ok := v.normalize()
+	if !ok {
+		return false
+	}
+	b, ok := RawValue(value).normalize()
+	return ok && a == b
+}
+
+// Has reports whether the value is an object with all the properties.
+func (v RawValue) Has(properties ...string) bool {
+	o, ok := v.Object()
+	if !ok {
+		return false
+	}
+	for _, property := range properties {
+		if _, ok := o[property]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Property returns a property of the value, if it is an object that has it.
+func (v RawValue) Property(property string) (RawValue, bool) {
+	o, ok := v.Object()
+	if !ok {
+		return nil, false
+	}
+	value, ok := o[property]
+	return value, ok
+}
+
+// Keys returns the sorted properties of the value, if it is an object.
+func (v RawValue) Keys() []string {
+	o, _ := v.Object()
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (v RawValue) String() (string, bool) {
+	var s string
+	return s, v.decode(&s)
+}
+
+func (v RawValue) Number() (float64, bool) {
+	var n float64
+	return n, v.decode(&n)
+}
+
+func (v RawValue) Integer() (int64, bool) {
+	var n int64
+	return n, v.decode(&n)
+}
+
+func (v RawValue) Boolean() (bool, bool) {
+	var b bool
+	return b, v.decode(&b)
+}
+
+func (v RawValue) Object() (map[string]RawValue, bool) {
+	var o map[string]RawValue
+	return o, v.decode(&o)
+}
+
+func (v RawValue) Array() ([]RawValue, bool) {
+	var a []RawValue
+	return a, v.decode(&a)
+}
+
+// Decode unmarshals the value into target, like json.Unmarshal.
+func (v RawValue) Decode(target any) error {
+	return json.Unmarshal(v, target)
+}
+
+func (v RawValue) decode(target any) bool {
+	return !v.IsNull() && json.Unmarshal(v, target) == nil
+}
+
+func (v RawValue) normalize() (string, bool) {
+	var value any
+	if err := json.Unmarshal(v, &value); err != nil {
+		return "", false
+	}
+	data, err := json.Marshal(value)
+	return string(data), err == nil
+}
+
+// An exact decimal number, the model of numbers with the decimal format. The
+// number is kept as written, so that it is encoded back exactly instead of
+// being rounded to a float64. Use Rat for arithmetic. The zero value is 0.
+type Decimal struct {
+	text string
+}
+
+// NewDecimal parses a decimal number, like "12.50" or "-1e3".
+func NewDecimal(s string) (Decimal, error) {
+	if !isNumber(s) {
+		return Decimal{}, NewFormatError(strconv.Quote(s), "decimal")
+	}
+	return Decimal{s}, nil
+}
+
+// NewDecimalFromRat rounds a rational number to the given number of digits
+// after the decimal point.
+func NewDecimalFromRat(r *big.Rat, scale int) Decimal {
+	return Decimal{r.FloatString(scale)}
+}
+
+func (d Decimal) Validate() error {
+	return nil
+}
+
+func (d Decimal) String() string {
+	if d.text == "" {
+		return "0"
+	}
+	return d.text
+}
+
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Float64 is the nearest float64 to the number.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Cmp compares the numbers, regardless of how they are written.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts JSON numbers and JSON strings with a number.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	text := string(data)
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		text = s
+	}
+	decimal, err := NewDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = decimal
+	return nil
+}
+
+// A Decimal that is encoded as a JSON string, the model of strings with the
+// decimal format.
+type DecimalString struct {
+	Decimal
+}
+
+func (d DecimalString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Whether a string is a JSON number.
+func isNumber(s string) bool {
+	if s == "" || s[0] != '-' && (s[0] < '0' || s[0] > '9') || s[len(s)-1] < '0' || s[len(s)-1] > '9' {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// Encodes an integer as a JSON string, so that JavaScript clients do not
+// round it.
+func marshalInt64String(v int64) ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(v, 10))
+}
+
+// Decodes an integer from a JSON string or a JSON number.
+func unmarshalInt64String(data []byte, v *int64) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return json.Unmarshal(data, v)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return NewFormatError(strconv.Quote(s), "int64")
+	}
+	*v = n
+	return nil
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -107,3 +109,205 @@ func TestTaskUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckDepth(t *testing.T) {
+	testCases := map[string]struct {
+		json        string
+		expectedErr bool
+	}{
+		"flat object": {
+			json: `{"name": "root"}`,
+		},
+		"nested up to the limit": {
+			json: strings.Repeat("[", MaxDepth) + strings.Repeat("]", MaxDepth),
+		},
+		"nested beyond the limit": {
+			json:        strings.Repeat("[", MaxDepth+1) + strings.Repeat("]", MaxDepth+1),
+			expectedErr: true,
+		},
+		"brackets in strings": {
+			json: `{"name": "` + strings.Repeat("[", MaxDepth+1) + `\"{"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := checkDepth([]byte(tc.json))
+			if tc.expectedErr {
+				assert.ErrorIs(t, err, ErrMaxDepth)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRawValue(t *testing.T) {
+	var values []RawValue
+	err := json.Unmarshal([]byte(`["text", 1, 1.5, true, {"a": null}, [1], null]`), &values)
+	require.NoError(t, err)
+
+	s, ok := values[0].String()
+	assert.True(t, ok)
+	assert.Equal(t, "text", s)
+	i, ok := values[1].Integer()
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), i)
+	_, ok = values[2].Integer()
+	assert.False(t, ok)
+	n, ok := values[2].Number()
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, n)
+	b, ok := values[3].Boolean()
+	assert.True(t, ok)
+	assert.True(t, b)
+	o, ok := values[4].Object()
+	assert.True(t, ok)
+	assert.True(t, o["a"].IsNull())
+	a, ok := values[5].Array()
+	assert.True(t, ok)
+	assert.Len(t, a, 1)
+	assert.True(t, values[6].IsNull())
+	_, ok = values[6].String()
+	assert.False(t, ok)
+
+	data, err := json.Marshal(values)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["text", 1, 1.5, true, {"a": null}, [1], null]`, string(data))
+}
+
+func TestNullUnmarshal(t *testing.T) {
+	var null Null
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &null))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{}`), &null), ErrType)
+	data, err := json.Marshal(Null{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
+
+func TestRawValueChecks(t *testing.T) {
+	assert.True(t, RawValue(`{"b": 1, "a": [1.0, "x"]}`).Equal(`{"a":[1,"x"],"b":1}`))
+	assert.False(t, RawValue(`"1"`).Equal(`1`))
+	assert.True(t, RawValue(`2.0`).Is("integer"))
+	assert.False(t, RawValue(`2.5`).Is("integer", "string"))
+	assert.True(t, RawValue(` "x"`).Is("string"))
+	assert.True(t, RawValue(`{"a": null, "b": 1}`).Has("a", "b"))
+	assert.False(t, RawValue(`{"a": 1}`).Has("a", "b"))
+	assert.False(t, RawValue(`["a"]`).Has("a"))
+	p, ok := RawValue(`{"a": {"b": true}}`).Property("a")
+	assert.True(t, ok)
+	assert.True(t, p.Has("b"))
+	assert.Equal(t, []string{"a", "b"}, RawValue(`{"b": 1, "a": 2}`).Keys())
+}
+
+func TestDecimal(t *testing.T) {
+	type Invoice struct {
+		Total    Decimal       `json:"total"`
+		Discount DecimalString `json:"discount"`
+	}
+	var invoice Invoice
+	err := json.Unmarshal([]byte(`{"total": 12345678901234567890.10, "discount": "0.30"}`), &invoice)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567890.10", invoice.Total.String())
+	assert.Equal(t, 0, invoice.Discount.Cmp(Decimal{"0.3"}))
+
+	data, err := json.Marshal(invoice)
+	require.NoError(t, err)
+	assert.Equal(t, `{"total":12345678901234567890.10,"discount":"0.30"}`, string(data))
+
+	_, err = NewDecimal("1/3")
+	assert.ErrorIs(t, err, ErrFormat)
+	assert.Equal(t, "0.33", NewDecimalFromRat(big.NewRat(1, 3), 2).String())
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"x"`), &invoice.Total), ErrFormat)
+}
+
+func TestInt64String(t *testing.T) {
+	var n int64
+	require.NoError(t, unmarshalInt64String([]byte(`"9007199254740993"`), &n))
+	assert.Equal(t, int64(9007199254740993), n)
+	require.NoError(t, unmarshalInt64String([]byte(`42`), &n))
+	assert.Equal(t, int64(42), n)
+	assert.ErrorIs(t, unmarshalInt64String([]byte(`"4.2"`), &n), ErrFormat)
+
+	data, err := marshalInt64String(9007199254740993)
+	require.NoError(t, err)
+	assert.Equal(t, `"9007199254740993"`, string(data))
+}
+
+func TestValidateHelpers(t *testing.T) {
+	assert.True(t, isMultipleOf(0.3, 0.1))
+	assert.False(t, isMultipleOf(0.35, 0.1))
+
+	i, j, ok := duplicateItems([]map[string]int{{"a": 1, "b": 2}, {"c": 3}, {"b": 2, "a": 1}})
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 2}, []int{i, j})
+	_, _, ok = duplicateItems([]string{"a", "b"})
+	assert.False(t, ok)
+
+	assert.NoError(t, validateResponse[RawValue]([]byte(`{"a": 1}`)))
+	assert.Error(t, validateResponse[Null]([]byte(`1`)))
+}
+
+func TestDecodeDefault(t *testing.T) {
+	var status *string
+	decodeDefault(`"available"`, &status)
+	require.NotNil(t, status)
+	assert.Equal(t, "available", *status)
+	count := 1
+	decodeDefault(`"x"`, &count)
+	assert.Equal(t, 1, count)
+}
+
+// A model with a writeOnly property in its items, like the generated ones.
+type accounts struct {
+	Owner    string    `json:"owner"`
+	Accounts []account `json:"accounts,omitempty"`
+}
+
+func (m *accounts) OmitWriteOnly() {
+	for i := range m.Accounts {
+		m.Accounts[i].OmitWriteOnly()
+	}
+}
+
+// A response body in memory.
+type testResponse struct {
+	body []byte
+}
+
+func (r *testResponse) Body() []byte {
+	return r.body
+}
+
+func (r *testResponse) SetBody(body []byte) {
+	r.body = body
+}
+
+func TestOmitWriteOnly(t *testing.T) {
+	testCases := map[string]struct {
+		body     string
+		expected string
+	}{
+		"nested": {
+			body:     `{"owner": "Alex", "accounts": [{"name": "a", "password": "x"}, {"name": "b"}]}`,
+			expected: `{"owner": "Alex", "accounts": [{"name": "a"}, {"name": "b"}]}`,
+		},
+		"undeclared properties": {
+			body:     `{"owner": "Alex", "extra": {"password": "x"}, "accounts": [{"name": "a", "password": "x", "id": 12.50}]}`,
+			expected: `{"owner": "Alex", "extra": {"password": "x"}, "accounts": [{"name": "a", "id": 12.50}]}`,
+		},
+		"invalid":  {body: `{"owner": 1, "accounts": [{"password": "x"}]}`, expected: `{"owner": 1, "accounts": [{"password": "x"}]}`},
+		"not JSON": {body: `Internal Server Error`, expected: `Internal Server Error`},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			response := &testResponse{[]byte(tc.body)}
+			omitWriteOnly[accounts](response)
+			if name == "not JSON" {
+				assert.Equal(t, tc.expected, string(response.Body()))
+				return
+			}
+			assert.JSONEq(t, tc.expected, string(response.Body()))
+		})
+	}
+}
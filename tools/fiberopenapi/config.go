@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/template"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// What a target generates.
+const (
+	FeatureServer  = "server"
+	FeatureClient  = "client"
+	FeatureModels  = "models"
+	FeatureRuntime = "runtime"
+)
+
+// The configuration file of fiberopenapi, in YAML or JSON, with the targets to
+// generate, like:
+//
+//	targets:
+//	  - spec: specs/pets.yaml
+//	    dir: pets
+//	    generate: [server, client, models]
+//	    exclude:
+//	      tags: [internal]
+type Config struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// A package generated from a specification. Paths are relative to the
+// configuration file, and empty fields have the defaults of the flags.
+type Target struct {
+	// Path to the specification.
+	Spec string `yaml:"spec"`
+	// Directory of the generated package, created if needed, and name of the
+	// package, which defaults to the one of the Go files in the directory, or
+	// to the name of the directory when it has none.
+	Dir     string `yaml:"dir"`
+	Package string `yaml:"package"`
+	// Names of the generated files in the directory.
+	HandlersFile string `yaml:"handlers-file"`
+	ClientFile   string `yaml:"client-file"`
+	ModelsFile   string `yaml:"models-file"`
+	// Names of the handlers interface and of the client.
+	TypeName       string `yaml:"type-name"`
+	ClientTypeName string `yaml:"client-type-name"`
+	// What to generate, among server, client, models and runtime. Defaults
+	// to server and models, or to server when the models are generated by
+	// another target.
+	Generate []string `yaml:"generate"`
+	// The packages of the models and of their runtime helpers, when they are
+	// not the package of the target. The models are generated in theirs, and
+	// the runtime in its own one. The server and the client import them.
+	ModelsPackage  *GoPackage `yaml:"models-package"`
+	RuntimePackage *GoPackage `yaml:"runtime-package"`
+	// Splits the operations of the handlers and of the client in a file for
+	// each tag, or for each operation.
+	Split string `yaml:"split"`
+	// Declares an interface for the handlers of each tag, like
+	// GameplayHandlers, that the handlers interface embeds.
+	Interfaces string `yaml:"interfaces"`
+	// Whether warnings fail the generation like errors do.
+	Strict        bool `yaml:"strict"`
+	Int64AsString bool `yaml:"int64-as-string"`
+	EmbedSpec     bool `yaml:"embed-spec"`
+	BundleSpec    bool `yaml:"bundle-spec"`
+	// Directory with templates that replace the embedded ones with the same
+	// file name, like handler.tmpl.
+	Templates string `yaml:"templates"`
+	// Go names to use instead of the ones derived from the specification.
+	Names NameOverrides `yaml:"names"`
+	// The operations to generate. Without include filters, all of them are,
+	// except the excluded ones. With filters, only the components that the
+	// operations use are.
+	Include OperationFilter `yaml:"include"`
+	Exclude OperationFilter `yaml:"exclude"`
+}
+
+// Reads a configuration file. The paths of its targets are made relative to
+// the current directory.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read configuration file at %s: %w", path, err)
+	}
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("cannot decode configuration file at %s: %w", path, err)
+	}
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("configuration file at %s has no targets", path)
+	}
+	base := filepath.Dir(path)
+	for i := range config.Targets {
+		target := &config.Targets[i]
+		if target.Spec == "" {
+			return nil, fmt.Errorf("target %d of %s has no spec", i, path)
+		}
+		target.setDefaults()
+		if err := target.validate(); err != nil {
+			return nil, fmt.Errorf("target %d of %s: %w", i, path, err)
+		}
+		target.Spec = filepath.Join(base, target.Spec)
+		target.Dir = filepath.Join(base, target.Dir)
+		if target.Templates != "" {
+			target.Templates = filepath.Join(base, target.Templates)
+		}
+		for _, pkg := range []*GoPackage{target.ModelsPackage, target.RuntimePackage} {
+			if pkg != nil && pkg.Dir != "" {
+				pkg.Dir = filepath.Join(base, pkg.Dir)
+			}
+		}
+	}
+	return &config, nil
+}
+
+func (t *Target) validate() error {
+	for _, feature := range t.Generate {
+		if !slices.Contains([]string{FeatureServer, FeatureClient, FeatureModels, FeatureRuntime}, feature) {
+			return fmt.Errorf("unknown feature %q", feature)
+		}
+	}
+	if !slices.Contains([]string{"", SplitByTag, SplitByOperation}, t.Split) {
+		return fmt.Errorf("unknown split %q, must be %s or %s", t.Split, SplitByTag, SplitByOperation)
+	}
+	if !slices.Contains([]string{"", InterfacesByTag}, t.Interfaces) {
+		return fmt.Errorf("unknown interfaces %q, must be %s", t.Interfaces, InterfacesByTag)
+	}
+	for name, filter := range map[string]OperationFilter{"include": t.Include, "exclude": t.Exclude} {
+		if err := filter.validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	for name, pkg := range map[string]*GoPackage{"models-package": t.ModelsPackage, "runtime-package": t.RuntimePackage} {
+		if pkg != nil && pkg.Import == "" {
+			return fmt.Errorf("%s has no import path", name)
+		}
+	}
+	// The server and the client use the runtime helpers, which can only be
+	// used from other packages in their own one.
+	if t.ModelsPackage != nil && t.RuntimePackage == nil {
+		return errors.New("models-package requires a runtime-package")
+	}
+	if slices.Contains(t.Generate, FeatureModels) && t.ModelsPackage != nil && t.ModelsPackage.Dir == "" {
+		return errors.New("models-package has no dir to generate the models in")
+	}
+	if slices.Contains(t.Generate, FeatureRuntime) && (t.RuntimePackage == nil || t.RuntimePackage.Dir == "") {
+		return errors.New("runtime requires a runtime-package with a dir to generate it in")
+	}
+	return nil
+}
+
+// Sets the defaults of the empty fields.
+func (t *Target) setDefaults() {
+	if t.HandlersFile == "" {
+		t.HandlersFile = "handlers.go"
+	}
+	if t.ClientFile == "" {
+		t.ClientFile = "client.go"
+	}
+	if t.ModelsFile == "" {
+		t.ModelsFile = "models.go"
+	}
+	if t.TypeName == "" {
+		t.TypeName = "Handlers"
+	}
+	if t.ClientTypeName == "" {
+		t.ClientTypeName = "Client"
+	}
+	if len(t.Generate) == 0 {
+		t.Generate = []string{FeatureServer, FeatureModels}
+		// Models without a directory are generated by another target.
+		if t.ModelsPackage != nil && t.ModelsPackage.Dir == "" {
+			t.Generate = []string{FeatureServer}
+		}
+	}
+}
+
+// Generates the files of the target to an output. The diagnostics of the
+// specification are returned, located in the specification file, even when
+// the generation fails. The ones found while generating are returned too, and
+// as the error.
+func (t Target) Run(output *Output) (Diagnostics, error) {
+	t.setDefaults()
+	spec, conversionDiagnostics, err := LoadOpenAPIDocument(t.Spec)
+	var loadDiagnostics Diagnostics
+	if errors.As(err, &loadDiagnostics) {
+		diagnostics := append(conversionDiagnostics, loadDiagnostics...)
+		t.locate(diagnostics)
+		return diagnostics, diagnostics
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Names.Apply(spec); err != nil {
+		return nil, err
+	}
+	t.Include.keep(spec, true)
+	t.Exclude.keep(spec, false)
+	if !t.Include.IsEmpty() || !t.Exclude.IsEmpty() {
+		pruneComponents(spec)
+	}
+
+	// Report all the problems of the specification at once, before
+	// generating anything. Then the names that the rest of the generated code
+	// declares can be found, and the models that collide with them are
+	// reported too.
+	options := ModelOptions{Int64AsString: t.Int64AsString}
+	if diagnostics, err := t.check(spec, conversionDiagnostics, options); err != nil {
+		return diagnostics, err
+	}
+	templates, err := LoadTemplates(t.Templates)
+	if err != nil {
+		return nil, err
+	}
+	if options, err = t.modelOptions(spec, templates); err != nil {
+		return nil, err
+	}
+	diagnostics, err := t.check(spec, conversionDiagnostics, options)
+	if err != nil {
+		return diagnostics, err
+	}
+	err = t.generate(spec, options, templates, output)
+	var generationDiagnostics Diagnostics
+	if errors.As(err, &generationDiagnostics) {
+		diagnostics = append(diagnostics, generationDiagnostics...)
+	}
+	t.locate(diagnostics)
+	return diagnostics, err
+}
+
+// The diagnostics of the operations and of their models, after the ones of
+// the conversion from Swagger. When some are errors, or warnings in strict
+// mode, they are located and returned as the error too.
+func (t Target) check(spec *libopenapi.DocumentModel[v3.Document], conversion Diagnostics, options ModelOptions) (Diagnostics, error) {
+	_, diagnostics := ExtractOperations(spec, options)
+	diagnostics = append(slices.Clone(conversion), diagnostics...)
+	if t.Strict {
+		for i := range diagnostics {
+			diagnostics[i].Severity = SeverityError
+		}
+	}
+	if diagnostics.HasErrors() {
+		t.locate(diagnostics)
+		return diagnostics, diagnostics
+	}
+	return diagnostics, nil
+}
+
+func (t Target) locate(diagnostics Diagnostics) {
+	if len(diagnostics) == 0 {
+		return
+	}
+	if data, err := os.ReadFile(t.Spec); err == nil {
+		diagnostics.Locate(data)
+	}
+}
+
+func (t Target) generate(spec *libopenapi.DocumentModel[v3.Document], options ModelOptions, templates *template.Template, output *Output) error {
+	generate := func(feature string) bool {
+		return slices.Contains(t.Generate, feature)
+	}
+	layout, err := t.layout(spec, options, templates)
+	if err != nil {
+		return err
+	}
+	if generate(FeatureRuntime) {
+		err := GenerateRuntime(t.RuntimePackage.PackageName(), filepath.Join(t.RuntimePackage.Dir, "runtime.go"), output)
+		if err != nil {
+			return err
+		}
+	}
+	if generate(FeatureModels) {
+		packageName, dir := t.Package, t.Dir
+		if t.ModelsPackage != nil {
+			packageName, dir = t.ModelsPackage.PackageName(), t.ModelsPackage.Dir
+		} else if packageName, err = t.packageName(); err != nil {
+			return err
+		}
+		err := GenerateModels(spec, packageName, filepath.Join(dir, t.ModelsFile),
+			options, layout, templates, output,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if !generate(FeatureServer) && !generate(FeatureClient) {
+		return nil
+	}
+	packageName, err := t.packageName()
+	if err != nil {
+		return err
+	}
+	if generate(FeatureServer) {
+		err := GenerateHandlers(spec, packageName, filepath.Join(t.Dir, t.HandlersFile), t.TypeName,
+			t.handlersOptions(options), layout, templates, output,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if generate(FeatureClient) {
+		err := GenerateClient(spec, packageName, filepath.Join(t.Dir, t.ClientFile), t.ClientTypeName, options, layout, templates, output)
+		if err != nil {
+			return err
+		}
+	}
+	// The specification is written last, as bundling modifies it.
+	if generate(FeatureServer) && t.EmbedSpec {
+		return GenerateSpec(spec, filepath.Dir(filepath.Join(t.Dir, t.HandlersFile)), t.BundleSpec, output)
+	}
+	return nil
+}
+
+// The options of the models of the target. When the models are in the package
+// of the handlers or of the client, the names that these declare are reserved,
+// and so are the ones of the runtime helpers in the models file.
+func (t Target) modelOptions(spec *libopenapi.DocumentModel[v3.Document], templates *template.Template) (ModelOptions, error) {
+	options := ModelOptions{Int64AsString: t.Int64AsString}
+	if t.ModelsPackage != nil {
+		return options, nil
+	}
+	if t.RuntimePackage == nil {
+		names, err := declaredNames([]byte(modelsFile))
+		if err != nil {
+			return ModelOptions{}, err
+		}
+		options.Reserved = append(options.Reserved, names...)
+	}
+	if slices.Contains(t.Generate, FeatureServer) {
+		names, err := HandlersNames(spec, t.TypeName, t.handlersOptions(options), templates)
+		if err != nil {
+			return ModelOptions{}, err
+		}
+		options.Reserved = append(options.Reserved, names...)
+	}
+	if slices.Contains(t.Generate, FeatureClient) {
+		names, err := ClientNames(spec, t.ClientTypeName, templates)
+		if err != nil {
+			return ModelOptions{}, err
+		}
+		options.Reserved = append(options.Reserved, names...)
+	}
+	return options, nil
+}
+
+func (t Target) handlersOptions(models ModelOptions) HandlersOptions {
+	return HandlersOptions{
+		EmbedSpec:     t.EmbedSpec,
+		TagInterfaces: t.Interfaces == InterfacesByTag,
+		Models:        models,
+	}
+}
+
+// The name of the package of the target, loaded from its directory unless it
+// is set.
+func (t Target) packageName() (string, error) {
+	if t.Package != "" {
+		return t.Package, nil
+	}
+	return LoadPackageName(t.Dir)
+}
+
+// How the code of the target is laid out, with the identifiers of the models
+// and of the runtime qualified when they are in other packages.
+func (t Target) layout(spec *libopenapi.DocumentModel[v3.Document], options ModelOptions, templates *template.Template) (Layout, error) {
+	layout := Layout{Split: t.Split}
+	if t.RuntimePackage == nil {
+		return layout, nil
+	}
+	layout.Qualifier = &Qualifier{}
+	layout.SharedRuntime = true
+	runtimeNames, err := declaredNames([]byte(modelsFile))
+	if err != nil {
+		return Layout{}, err
+	}
+	layout.Qualifier.Add(*t.RuntimePackage, runtimeNames, true)
+	if t.ModelsPackage != nil {
+		modelNames, err := ModelNames(spec, options, templates)
+		if err != nil {
+			return Layout{}, err
+		}
+		layout.Qualifier.Add(*t.ModelsPackage, modelNames, false)
+	}
+	return layout, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := map[string]struct {
+		config   string
+		expected []Target
+		err      string
+	}{
+		"relative paths": {
+			config: `
+targets:
+  - spec: specs/pets.yaml
+    dir: pets
+    generate: [client, models]
+    exclude:
+      tags: [internal]
+  - spec: specs/pets.yaml
+`,
+			expected: []Target{{
+				Spec:     filepath.Join("specs", "pets.yaml"),
+				Dir:      "pets",
+				Generate: []string{FeatureClient, FeatureModels},
+				Exclude:  OperationFilter{Tags: []string{"internal"}},
+			}, {
+				Spec: filepath.Join("specs", "pets.yaml"),
+			}},
+		},
+		"imported models": {
+			config: `
+targets:
+  - spec: pets.yaml
+    split: tag
+    models-package: {import: example.com/pets/models}
+    runtime-package: {import: example.com/pets/runtime, dir: runtime}
+`,
+			expected: []Target{{
+				Spec:           "pets.yaml",
+				Generate:       []string{FeatureServer},
+				ModelsPackage:  &GoPackage{Import: "example.com/pets/models"},
+				RuntimePackage: &GoPackage{Import: "example.com/pets/runtime", Dir: "runtime"},
+				Split:          SplitByTag,
+			}},
+		},
+		"templates": {
+			config: "targets:\n  - spec: pets.yaml\n    templates: templates\n",
+			expected: []Target{{
+				Spec:      "pets.yaml",
+				Templates: "templates",
+			}},
+		},
+		"interfaces and filters": {
+			config: `
+targets:
+  - spec: pets.yaml
+    interfaces: tag
+    include: {paths: [/pets], operations: [list-*]}
+    exclude: {internal: true}
+`,
+			expected: []Target{{
+				Spec:       "pets.yaml",
+				Interfaces: InterfacesByTag,
+				Include:    OperationFilter{Paths: []string{"/pets"}, Operations: []string{"list-*"}},
+				Exclude:    OperationFilter{Internal: true},
+			}},
+		},
+		"unknown interfaces": {
+			config: "targets:\n  - spec: pets.yaml\n    interfaces: path\n",
+			err:    `unknown interfaces "path"`,
+		},
+		"invalid operation glob": {
+			config: "targets:\n  - spec: pets.yaml\n    exclude: {operations: [\"[\"]}\n",
+			err:    `exclude: invalid operation glob "["`,
+		},
+		"models package without runtime package": {
+			config: "targets:\n  - spec: pets.yaml\n    models-package: {import: example.com/pets/models, dir: models}\n",
+			err:    "models-package requires a runtime-package",
+		},
+		"runtime without dir": {
+			config: "targets:\n  - spec: pets.yaml\n    generate: [runtime]\n    runtime-package: {import: example.com/pets/runtime}\n",
+			err:    "runtime requires a runtime-package with a dir",
+		},
+		"unknown split": {
+			config: "targets:\n  - spec: pets.yaml\n    split: path\n",
+			err:    `unknown split "path"`,
+		},
+		"unknown field": {
+			config: "targets:\n  - spec: pets.yaml\n    output: pets.go\n",
+			err:    "field output not found",
+		},
+		"unknown feature": {
+			config: "targets:\n  - spec: pets.yaml\n    generate: [router]\n",
+			err:    `unknown feature "router"`,
+		},
+		"no spec": {
+			config: "targets:\n  - dir: pets\n",
+			err:    "has no spec",
+		},
+		"no targets": {
+			config: "targets: []\n",
+			err:    "has no targets",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "fiberopenapi.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(test.config), 0o644))
+
+			config, err := LoadConfig(path)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			// Empty fields have their defaults.
+			for i := range test.expected {
+				test.expected[i].setDefaults()
+				test.expected[i].Spec = filepath.Join(dir, test.expected[i].Spec)
+				test.expected[i].Dir = filepath.Join(dir, test.expected[i].Dir)
+				if test.expected[i].Templates != "" {
+					test.expected[i].Templates = filepath.Join(dir, test.expected[i].Templates)
+				}
+				if pkg := test.expected[i].RuntimePackage; pkg != nil && pkg.Dir != "" {
+					pkg.Dir = filepath.Join(dir, pkg.Dir)
+				}
+			}
+			assert.Equal(t, test.expected, config.Targets)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// A problem found in the specification, at a JSON pointer like
+// "#/components/schemas/Pet/properties/name". The line and column of the
+// pointer in the specification file are set by Locate, and are 0 until then.
+type Diagnostic struct {
+	Severity Severity
+	Message  string
+	Pointer  string
+	Line     int
+	Column   int
+}
+
+func (d Diagnostic) Error() string {
+	if d.Pointer == "" {
+		return d.Message
+	}
+	return d.Pointer + ": " + d.Message
+}
+
+// Formats the diagnostic like compilers do, like
+// "spec.json:12:7: error: #/paths/~1pets/get: operationId is empty".
+func (d Diagnostic) Format(file string) string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", file, d.Severity, d.Error())
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", file, d.Line, d.Column, d.Severity, d.Error())
+}
+
+// Stops building the model of the schema or writing the code that failed,
+// with a diagnostic at pointer. The diagnostic is recovered by the nearest
+// modelBuilder.NewModel, which goes on with the rest of the specification, or
+// by recoverDiagnostic.
+func failf(pointer, format string, args ...any) {
+	panic(Diagnostic{
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+		Pointer:  pointer,
+	})
+}
+
+// Returns the diagnostic of a failure as err, and panics again with anything
+// else, which is a bug.
+func recoverDiagnostic(err *error) {
+	if r := recover(); r != nil {
+		diagnostic, ok := r.(Diagnostic)
+		if !ok {
+			panic(r)
+		}
+		*err = Diagnostics{diagnostic}
+	}
+}
+
+// The diagnostics of a specification, in the order they were found. As an
+// error, only the ones with the error severity are returned, and all of them
+// are listed.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	var messages []string
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			messages = append(messages, diagnostic.Error())
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// The diagnostics as an error if any of them is an error, or nil.
+func (d Diagnostics) Err() error {
+	if d.HasErrors() {
+		return d
+	}
+	return nil
+}
+
+// Sets the line and column of the diagnostics from the specification file
+// they were found in. A pointer that cannot be followed to the end, like the
+// one of a schema in another file, is located at its last node that can. The
+// pointers of a Swagger 2.0 specification converted to OpenAPI 3 are located
+// where they were converted from.
+func (d Diagnostics) Locate(spec []byte) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil || len(root.Content) == 0 {
+		return
+	}
+	swagger := isSwagger(&root)
+	for i := range d {
+		if d[i].Pointer == "" {
+			continue
+		}
+		pointer := d[i].Pointer
+		if swagger {
+			pointer = swaggerPointer(pointer)
+		}
+		node := locatePointer(root.Content[0], strings.TrimPrefix(pointer, "#"))
+		d[i].Line, d[i].Column = node.Line, node.Column
+	}
+}
+
+// The deepest node of a JSON pointer, which is the key of the last property
+// that can be followed.
+func locatePointer(node *yaml.Node, pointer string) *yaml.Node {
+	located := node
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		switch node.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					located, node, found = node.Content[i], node.Content[i+1], true
+					break
+				}
+			}
+			if !found {
+				return located
+			}
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return located
+			}
+			located, node = node.Content[i], node.Content[i]
+		default:
+			return located
+		}
+	}
+	return located
+}
+
+// The JSON pointer of the node at a line and column, the inverse of
+// locatePointer, and whether there is one. The key of a property is located at
+// its value, except the key of a $ref, which is located at the object that
+// has it, as that is where the reference is.
+func pointerAt(node *yaml.Node, line, column int) (string, bool) {
+	if node.Line == line && node.Column == column {
+		return "", true
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			segment := "/" + EscapeJSONPointer(key.Value)
+			if key.Line == line && key.Column == column {
+				if key.Value == "$ref" {
+					return "", true
+				}
+				return segment, true
+			}
+			if pointer, ok := pointerAt(node.Content[i+1], line, column); ok {
+				return segment + pointer, true
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			if pointer, ok := pointerAt(item, line, column); ok {
+				return "/" + strconv.Itoa(i) + pointer, true
+			}
+		}
+	}
+	return "", false
+}
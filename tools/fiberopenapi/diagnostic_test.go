@@ -0,0 +1,158 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticFormat(t *testing.T) {
+	diagnostic := Diagnostic{
+		Severity: SeverityError,
+		Message:  "operationId is empty",
+		Pointer:  "#/paths/~1pets/get",
+	}
+	assert.Equal(t, "spec.json: error: #/paths/~1pets/get: operationId is empty",
+		diagnostic.Format("spec.json"),
+	)
+	diagnostic.Line, diagnostic.Column = 12, 7
+	assert.Equal(t, "spec.json:12:7: error: #/paths/~1pets/get: operationId is empty",
+		diagnostic.Format("spec.json"),
+	)
+}
+
+func TestDiagnosticsLocate(t *testing.T) {
+	spec := []byte(`{
+  "paths": {
+    "/pets": {
+      "get": {"responses": {}}
+    }
+  },
+  "tags": [{"name": "a"}, {"name": "b"}]
+}`)
+	diagnostics := Diagnostics{
+		{Pointer: "#/paths/~1pets/get"},
+		{Pointer: "#/tags/1/name"},
+		// Pointers that cannot be followed are located at their last node.
+		{Pointer: "#/paths/~1pets/post/requestBody"},
+		{Pointer: ""},
+	}
+	diagnostics.Locate(spec)
+	for i, expected := range [][2]int{{4, 7}, {7, 28}, {3, 5}, {0, 0}} {
+		assert.Equal(t, expected, [2]int{diagnostics[i].Line, diagnostics[i].Column}, diagnostics[i].Pointer)
+	}
+}
+
+func TestExtractionDiagnostics(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Diagnostics", "version": "1.0.0"},
+		"paths": {
+			"/pets": {
+				"get": {"responses": {
+					"200": {"description": "The pets", "content": {"text/plain": {}}}
+				}},
+				"post": {
+					"operationId": "add-pet",
+					"requestBody": {"content": {"text/plain": {}}},
+					"responses": {}
+				}
+			},
+			"/pets/{id}": {
+				"put": {
+					"operationId": "update-pet",
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "content": {"application/json": {"schema": {"type": "integer"}}}},
+						{"name": "dry-run", "in": "query"}
+					],
+					"requestBody": {"content": {"application/json": {}}},
+					"responses": {}
+				}
+			}
+		},
+		"components": {"schemas": {
+			"code": {"type": "string", "pattern": "^(?=a)"},
+			"list": {"type": "array"},
+			"names": {"type": "object", "propertyNames": {"maxLength": 3}}
+		}}
+	}`), ".")
+	require.NoError(t, err)
+
+	// All the problems are reported at once.
+	_, diagnostics := ExtractOperations(spec, ModelOptions{})
+	assert.True(t, diagnostics.HasErrors())
+	assert.EqualError(t, diagnostics,
+		"#/components/schemas/code/pattern: error parsing regexp: invalid or unsupported Perl syntax: `(?=`\n"+
+			"#/components/schemas/list: array type must have an items property\n"+
+			"#/components/schemas/names: keyword propertyNames is not supported\n"+
+			"#/paths/~1pets/get: operationId is empty\n"+
+			"#/paths/~1pets/post/requestBody: no application/json content for request body of operation add-pet\n"+
+			"#/paths/~1pets~1{id}/put/requestBody/content/application~1json: no schema for the application/json content of the request body of operation update-pet\n"+
+			"#/paths/~1pets~1{id}/put/parameters/0/content: parameter id has content instead of a schema, which is not supported\n"+
+			"#/paths/~1pets~1{id}/put/parameters/1: parameter dry-run has no schema",
+	)
+}
+
+func TestExtractionWarnings(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Diagnostics", "version": "1.0.0"},
+		"paths": {
+			"/pets": {
+				"get": {"operationId": "list-pets", "responses": {
+					"200": {"description": "The pets", "content": {"text/plain": {}}}
+				}}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	operations, diagnostics := ExtractOperations(spec, ModelOptions{})
+	assert.Len(t, operations, 1)
+	assert.NoError(t, diagnostics.Err())
+	assert.Equal(t, Diagnostics{{
+		Severity: SeverityWarning,
+		Message:  "no application/json content for response 200, its body is not typed nor validated",
+		Pointer:  "#/paths/~1pets/get/responses/200/content",
+	}}, diagnostics)
+}
+
+func TestModelDiagnostics(t *testing.T) {
+	_, err := loadOpenAPIDocument([]byte(`openapi: 3.1.0
+info: {title: Diagnostics, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: list-pets
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pets"}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: "#/components/schemas/Owner"
+`), ".")
+	// Each reference that cannot be resolved is reported once, at the object
+	// with the reference.
+	var diagnostics Diagnostics
+	require.ErrorAs(t, err, &diagnostics)
+	assert.Equal(t, Diagnostics{
+		{
+			Severity: SeverityError,
+			Message:  "component `#/components/schemas/Pets` does not exist in the specification",
+			Pointer:  "#/paths/~1pets/get/responses/200/content/application~1json/schema",
+		},
+		{
+			Severity: SeverityError,
+			Message:  "component `#/components/schemas/Owner` does not exist in the specification",
+			Pointer:  "#/components/schemas/Pet/properties/owner",
+		},
+	}, diagnostics)
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// A change between two versions of a specification, and whom it breaks.
+// Clients break when the requests that they send are no longer valid, or when
+// they receive responses that were not valid before. Servers break when they
+// receive requests that were not valid before, or when the responses that they
+// send are no longer valid.
+type Change struct {
+	// JSON pointer to what changed, in the new specification, or in the old
+	// one when it was removed.
+	Pointer       string `json:"pointer"`
+	Message       string `json:"message"`
+	BreaksClients bool   `json:"breaksClients"`
+	BreaksServers bool   `json:"breaksServers"`
+}
+
+func (c Change) Breaking() bool {
+	return c.BreaksClients || c.BreaksServers
+}
+
+// Formats the change like a diagnostic, like
+// "#/paths/~1pets/get: breaking for clients: operation list-pets was removed".
+func (c Change) String() string {
+	kind := "non-breaking"
+	switch {
+	case c.BreaksClients && c.BreaksServers:
+		kind = "breaking for clients and servers"
+	case c.BreaksClients:
+		kind = "breaking for clients"
+	case c.BreaksServers:
+		kind = "breaking for servers"
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Pointer, kind, c.Message)
+}
+
+type Changes []Change
+
+func (c Changes) Breaking() bool {
+	for _, change := range c {
+		if change.Breaking() {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the messages of a change are requests, which clients send and
+// servers receive, or responses.
+type messageDirection int
+
+const (
+	requestMessage messageDirection = iota
+	responseMessage
+)
+
+func (d messageDirection) String() string {
+	if d == requestMessage {
+		return "request"
+	}
+	return "response"
+}
+
+// How a change affects the messages that are valid.
+type changeEffect int
+
+const (
+	// The same messages are valid.
+	compatibleChange changeEffect = iota
+	// Some messages that were valid are not anymore.
+	narrowingChange
+	// Some messages that were not valid are now.
+	wideningChange
+	// Both.
+	incompatibleChange
+)
+
+// Compares two versions of a specification. Operations are matched by method
+// and path, regardless of the names of the path parameters, and schemas are
+// compared where the operations use them, in their requests and responses.
+func DiffSpecs(old, new *libopenapi.DocumentModel[v3.Document]) Changes {
+	d := &specDiff{index: map[string]int{}, compared: map[string]bool{}}
+	oldOperations := specOperations(old)
+	newOperations := specOperations(new)
+	for pair := oldOperations.First(); pair != nil; pair = pair.Next() {
+		oldOperation := pair.Value()
+		newOperation, ok := newOperations.Get(pair.Key())
+		if !ok {
+			d.report(oldOperation.pointer, narrowingChange, requestMessage,
+				"operation %s was removed", oldOperation.name())
+			continue
+		}
+		d.compareOperations(oldOperation, newOperation)
+	}
+	for pair := newOperations.First(); pair != nil; pair = pair.Next() {
+		if _, ok := oldOperations.Get(pair.Key()); !ok {
+			d.report(pair.Value().pointer, wideningChange, requestMessage,
+				"operation %s was added", pair.Value().name())
+		}
+	}
+	return d.changes
+}
+
+type specDiff struct {
+	changes Changes
+	// The positions of the changes by pointer and message, to merge the ones
+	// of the schemas used both in requests and in responses.
+	index map[string]int
+	// The pairs of referenced schemas compared in each direction, which stops
+	// the comparison of recursive schemas.
+	compared map[string]bool
+}
+
+// Reports a change of the messages in a direction, and whom it breaks.
+func (d *specDiff) report(pointer string, effect changeEffect, direction messageDirection, format string, args ...any) {
+	change := Change{Pointer: pointer, Message: fmt.Sprintf(format, args...)}
+	switch {
+	case effect == incompatibleChange:
+		change.BreaksClients, change.BreaksServers = true, true
+	case effect == narrowingChange && direction == requestMessage,
+		effect == wideningChange && direction == responseMessage:
+		change.BreaksClients = true
+	case effect == narrowingChange && direction == responseMessage,
+		effect == wideningChange && direction == requestMessage:
+		change.BreaksServers = true
+	}
+	key := change.Pointer + "\n" + change.Message
+	if i, ok := d.index[key]; ok {
+		d.changes[i].BreaksClients = d.changes[i].BreaksClients || change.BreaksClients
+		d.changes[i].BreaksServers = d.changes[i].BreaksServers || change.BreaksServers
+		return
+	}
+	d.index[key] = len(d.changes)
+	d.changes = append(d.changes, change)
+}
+
+// An operation of a specification, with the parameters of its path item.
+type specOperation struct {
+	*v3.Operation
+	pointer string
+	// The parameters by location and name, or by position for the ones of
+	// the path, with their pointers.
+	parameters *orderedmap.Map[string, specParameter]
+}
+
+type specParameter struct {
+	*v3.Parameter
+	pointer string
+}
+
+func (o specOperation) name() string {
+	if o.OperationId != "" {
+		return o.OperationId
+	}
+	return o.pointer
+}
+
+// The operations of a specification by method and path, with the names of the
+// path parameters removed, like "get /pets/{}".
+func specOperations(spec *libopenapi.DocumentModel[v3.Document]) *orderedmap.Map[string, specOperation] {
+	operations := orderedmap.New[string, specOperation]()
+	if spec.Model.Paths == nil {
+		return operations
+	}
+	for pair := spec.Model.Paths.PathItems.First(); pair != nil; pair = pair.Next() {
+		path, pathItem := pair.Key(), pair.Value()
+		pathPointer := "#/paths/" + EscapeJSONPointer(path)
+		for operationPair := pathItem.GetOperations().First(); operationPair != nil; operationPair = operationPair.Next() {
+			method, operation := operationPair.Key(), operationPair.Value()
+			result := specOperation{
+				Operation:  operation,
+				pointer:    pathPointer + "/" + method,
+				parameters: orderedmap.New[string, specParameter](),
+			}
+			// The parameters of the operation override the ones of the path
+			// item.
+			for i, parameter := range pathItem.Parameters {
+				result.parameters.Set(parameterKey(path, parameter), specParameter{
+					Parameter: parameter,
+					pointer:   pathPointer + "/parameters/" + strconv.Itoa(i),
+				})
+			}
+			for i, parameter := range operation.Parameters {
+				result.parameters.Set(parameterKey(path, parameter), specParameter{
+					Parameter: parameter,
+					pointer:   result.pointer + "/parameters/" + strconv.Itoa(i),
+				})
+			}
+			key := method + " " + openApiPathParamPattern.ReplaceAllString(path, "{}")
+			operations.Set(key, result)
+		}
+	}
+	return operations
+}
+
+// Identifies a parameter by its location and name, or by its position in the
+// path for path parameters, which can be renamed without changing the
+// requests.
+func parameterKey(path string, parameter *v3.Parameter) string {
+	if parameter.In == "path" {
+		for i, match := range openApiPathParamPattern.FindAllStringSubmatch(path, -1) {
+			if match[1] == parameter.Name {
+				return "path " + strconv.Itoa(i)
+			}
+		}
+	}
+	return parameter.In + " " + parameter.Name
+}
+
+func (d *specDiff) compareOperations(old, new specOperation) {
+	if old.OperationId != "" && new.OperationId != "" && old.OperationId != new.OperationId {
+		d.report(new.pointer+"/operationId", incompatibleChange, requestMessage,
+			"operationId changed from %s to %s, which renames its generated methods", old.OperationId, new.OperationId)
+	}
+	isDeprecated := func(operation specOperation) bool {
+		return operation.Deprecated != nil && *operation.Deprecated
+	}
+	if !isDeprecated(old) && isDeprecated(new) {
+		d.report(new.pointer, compatibleChange, requestMessage, "operation %s was deprecated", new.name())
+	}
+	d.compareParameters(old, new)
+	d.compareRequestBodies(old, new)
+	d.compareResponses(old, new)
+}
+
+func (d *specDiff) compareParameters(old, new specOperation) {
+	for pair := old.parameters.First(); pair != nil; pair = pair.Next() {
+		oldParameter := pair.Value()
+		newParameter, ok := new.parameters.Get(pair.Key())
+		if !ok {
+			if isRequired(oldParameter.Parameter) {
+				d.report(oldParameter.pointer, wideningChange, requestMessage,
+					"required %s parameter %s was removed", oldParameter.In, oldParameter.Name)
+			} else {
+				d.report(oldParameter.pointer, compatibleChange, requestMessage,
+					"optional %s parameter %s was removed", oldParameter.In, oldParameter.Name)
+			}
+			continue
+		}
+		switch {
+		case !isRequired(oldParameter.Parameter) && isRequired(newParameter.Parameter):
+			d.report(newParameter.pointer, narrowingChange, requestMessage,
+				"%s parameter %s is now required", newParameter.In, newParameter.Name)
+		case isRequired(oldParameter.Parameter) && !isRequired(newParameter.Parameter):
+			d.report(newParameter.pointer, wideningChange, requestMessage,
+				"%s parameter %s is no longer required", newParameter.In, newParameter.Name)
+		}
+		d.compareSchemas(newParameter.pointer+"/schema", oldParameter.Schema, newParameter.Schema, requestMessage)
+	}
+	for pair := new.parameters.First(); pair != nil; pair = pair.Next() {
+		newParameter := pair.Value()
+		if _, ok := old.parameters.Get(pair.Key()); ok {
+			continue
+		}
+		if isRequired(newParameter.Parameter) {
+			d.report(newParameter.pointer, narrowingChange, requestMessage,
+				"required %s parameter %s was added", newParameter.In, newParameter.Name)
+		} else {
+			d.report(newParameter.pointer, compatibleChange, requestMessage,
+				"optional %s parameter %s was added", newParameter.In, newParameter.Name)
+		}
+	}
+}
+
+// Whether a parameter is required, as the ones of the path always are.
+func isRequired(parameter *v3.Parameter) bool {
+	return parameter.In == "path" || parameter.Required != nil && *parameter.Required
+}
+
+func (d *specDiff) compareRequestBodies(old, new specOperation) {
+	pointer := new.pointer + "/requestBody"
+	oldRequired := old.RequestBody != nil && old.RequestBody.Required != nil && *old.RequestBody.Required
+	newRequired := new.RequestBody != nil && new.RequestBody.Required != nil && *new.RequestBody.Required
+	switch {
+	case old.RequestBody == nil && new.RequestBody == nil:
+		return
+	case new.RequestBody == nil:
+		d.report(old.pointer+"/requestBody", wideningChange, requestMessage, "request body was removed")
+		return
+	case old.RequestBody == nil && newRequired:
+		d.report(pointer, narrowingChange, requestMessage, "required request body was added")
+		return
+	case old.RequestBody == nil:
+		d.report(pointer, compatibleChange, requestMessage, "optional request body was added")
+		return
+	case !oldRequired && newRequired:
+		d.report(pointer, narrowingChange, requestMessage, "request body is now required")
+	case oldRequired && !newRequired:
+		d.report(pointer, wideningChange, requestMessage, "request body is no longer required")
+	}
+	d.compareContents(pointer+"/content", old.RequestBody.Content, new.RequestBody.Content, requestMessage)
+}
+
+func (d *specDiff) compareResponses(old, new specOperation) {
+	oldResponses, newResponses := operationResponses(old), operationResponses(new)
+	for pair := oldResponses.First(); pair != nil; pair = pair.Next() {
+		code, oldResponse := pair.Key(), pair.Value()
+		newResponse, ok := newResponses.Get(code)
+		if !ok {
+			d.report(old.pointer+"/responses/"+code, narrowingChange, responseMessage,
+				"response %s was removed", code)
+			continue
+		}
+		d.compareContents(new.pointer+"/responses/"+code+"/content",
+			oldResponse.Content, newResponse.Content, responseMessage,
+		)
+	}
+	for pair := newResponses.First(); pair != nil; pair = pair.Next() {
+		if _, ok := oldResponses.Get(pair.Key()); !ok {
+			d.report(new.pointer+"/responses/"+pair.Key(), wideningChange, responseMessage,
+				"response %s was added", pair.Key())
+		}
+	}
+}
+
+// The responses of an operation by status code, with the default one.
+func operationResponses(operation specOperation) *orderedmap.Map[string, *v3.Response] {
+	responses := orderedmap.New[string, *v3.Response]()
+	if operation.Responses == nil {
+		return responses
+	}
+	for pair := operation.Responses.Codes.First(); pair != nil; pair = pair.Next() {
+		responses.Set(pair.Key(), pair.Value())
+	}
+	if operation.Responses.Default != nil {
+		responses.Set("default", operation.Responses.Default)
+	}
+	return responses
+}
+
+// Compares the schemas of the media types of a request body or a response.
+func (d *specDiff) compareContents(pointer string, old, new *orderedmap.Map[string, *v3.MediaType], direction messageDirection) {
+	if old == nil {
+		old = orderedmap.New[string, *v3.MediaType]()
+	}
+	if new == nil {
+		new = orderedmap.New[string, *v3.MediaType]()
+	}
+	for pair := old.First(); pair != nil; pair = pair.Next() {
+		mediaType := pair.Key()
+		newMediaType, ok := new.Get(mediaType)
+		if !ok {
+			d.report(pointer, narrowingChange, direction, "%s media type %s was removed", direction, mediaType)
+			continue
+		}
+		d.compareSchemas(pointer+"/"+EscapeJSONPointer(mediaType)+"/schema",
+			pair.Value().Schema, newMediaType.Schema, direction,
+		)
+	}
+	for pair := new.First(); pair != nil; pair = pair.Next() {
+		if _, ok := old.Get(pair.Key()); !ok {
+			d.report(pointer, wideningChange, direction, "%s media type %s was added", direction, pair.Key())
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Runs the diff subcommand, which compares two versions of a specification,
+// like:
+//
+//	fiberopenapi diff old.yaml new.yaml
+//	fiberopenapi diff -rev main openapi.yaml
+//
+// It returns the exit code: 1 when a change is breaking, and 2 on errors.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fiberopenapi diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "format of the changes; either text or json")
+	revision := flags.String("rev", "", "git revision of the old specification, to compare it with the one in the working tree")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: fiberopenapi diff [flags] old-spec new-spec")
+		fmt.Fprintln(stderr, "       fiberopenapi diff [flags] -rev revision spec")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "fiberopenapi: unknown format %q, must be text or json\n", *format)
+		return 2
+	}
+
+	// Swagger 2.0 specifications are compared once converted. The warnings
+	// about what does not convert concern the generated code, and are not
+	// reported.
+	var old, new *libopenapi.DocumentModel[v3.Document]
+	var err error
+	switch {
+	case *revision != "" && flags.NArg() == 1:
+		old, err = loadRevision(*revision, flags.Arg(0))
+		if err == nil {
+			new, _, err = LoadOpenAPIDocument(flags.Arg(0))
+		}
+	case *revision == "" && flags.NArg() == 2:
+		old, _, err = LoadOpenAPIDocument(flags.Arg(0))
+		if err == nil {
+			new, _, err = LoadOpenAPIDocument(flags.Arg(1))
+		}
+	default:
+		flags.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "fiberopenapi: %v\n", err)
+		return 2
+	}
+
+	changes := DiffSpecs(old, new)
+	if *format == "json" {
+		if changes == nil {
+			changes = Changes{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			fmt.Fprintf(stderr, "fiberopenapi: %v\n", err)
+			return 2
+		}
+	} else {
+		for _, change := range changes {
+			fmt.Fprintln(stdout, change)
+		}
+	}
+	if changes.Breaking() {
+		return 1
+	}
+	return 0
+}
+
+// Loads a specification as it was in a git revision. Its references to other
+// files are resolved in the same revision, extracted to a temporary directory.
+func loadRevision(revision, path string) (*libopenapi.DocumentModel[v3.Document], error) {
+	dir := filepath.Dir(path)
+	// The path is relative to the directory of the specification, which is
+	// where git runs.
+	data, err := git(dir, "show", revision+":./"+filepath.ToSlash(filepath.Base(path)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s at revision %s: %w", path, revision, err)
+	}
+	data, _, err = ConvertSwagger(data)
+	if err != nil {
+		return nil, err
+	}
+	if !hasFileReferences(data) {
+		return loadOpenAPIDocument(data, dir)
+	}
+	// The whole tree is extracted, as references can point anywhere in it,
+	// like ../common/schemas.yaml.
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s at revision %s: %w", path, revision, err)
+	}
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s at revision %s: %w", path, revision, err)
+	}
+	archive, err := git(strings.TrimSpace(string(root)), "archive", "--format=tar", revision)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s at revision %s: %w", path, revision, err)
+	}
+	tree, err := os.MkdirTemp("", "fiberopenapi-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tree)
+	if err := extractTar(bytes.NewReader(archive), tree); err != nil {
+		return nil, fmt.Errorf("cannot read %s at revision %s: %w", path, revision, err)
+	}
+	return loadOpenAPIDocument(data, filepath.Join(tree, filepath.FromSlash(strings.TrimSpace(string(prefix)))))
+}
+
+// Runs a git command in a directory and returns its output.
+func git(dir string, args ...string) ([]byte, error) {
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	output, err := command.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, errors.New(string(bytes.TrimSpace(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// Whether a specification references other files, with a $ref that is not
+// only a fragment like #/components/schemas/Pet.
+func hasFileReferences(spec []byte) bool {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return false
+	}
+	var walk func(node *yaml.Node) bool
+	walk = func(node *yaml.Node) bool {
+		for i, child := range node.Content {
+			if node.Kind == yaml.MappingNode && i%2 == 0 && child.Value == "$ref" &&
+				!strings.HasPrefix(node.Content[i+1].Value, "#") {
+				return true
+			}
+			if walk(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(&root)
+}
+
+// Extracts the directories and the regular files of a tar archive, like the
+// ones of git archive, into a directory.
+func extractTar(r io.Reader, dir string) error {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			file, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, archive)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
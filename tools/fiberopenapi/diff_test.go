@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A specification with the paths and the component schemas, in JSON.
+func diffSpec(paths, schemas string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Diff", "version": "1.0.0"},
+		"paths": {%s},
+		"components": {"schemas": {%s}}
+	}`, paths, schemas))
+}
+
+func TestDiffSpecs(t *testing.T) {
+	listPets := `"/pets": {"get": {"operationId": "list-pets", "responses": {"200": {"description": "OK"}}}}`
+	tests := map[string]struct {
+		oldPaths, newPaths     string
+		oldSchemas, newSchemas string
+		expected               Changes
+	}{
+		"no changes": {
+			oldPaths: listPets,
+			newPaths: listPets,
+		},
+		"removed operation": {
+			oldPaths: listPets,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get",
+				Message:       "operation list-pets was removed",
+				BreaksClients: true,
+			}},
+		},
+		"added operation": {
+			newPaths: listPets,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get",
+				Message:       "operation list-pets was added",
+				BreaksServers: true,
+			}},
+		},
+		"required parameter added": {
+			oldPaths: `"/pets": {"get": {"responses": {}}}`,
+			newPaths: `"/pets": {"get": {"parameters": [{"name": "limit", "in": "query", "required": true, "schema": {"type": "integer"}}], "responses": {}}}`,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get/parameters/0",
+				Message:       "required query parameter limit was added",
+				BreaksClients: true,
+			}},
+		},
+		"optional parameter added": {
+			oldPaths: `"/pets": {"get": {"responses": {}}}`,
+			newPaths: `"/pets": {"get": {"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}], "responses": {}}}`,
+			expected: Changes{{
+				Pointer: "#/paths/~1pets/get/parameters/0",
+				Message: "optional query parameter limit was added",
+			}},
+		},
+		"renamed path parameter": {
+			oldPaths: `"/pets/{id}": {"get": {"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}], "responses": {}}}`,
+			newPaths: `"/pets/{petId}": {"get": {"parameters": [{"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}], "responses": {}}}`,
+		},
+		"narrowed request enum": {
+			oldPaths: `"/pets": {"get": {"parameters": [{"name": "kind", "in": "query", "schema": {"enum": ["cat", "dog"]}}], "responses": {}}}`,
+			newPaths: `"/pets": {"get": {"parameters": [{"name": "kind", "in": "query", "schema": {"enum": ["cat"]}}], "responses": {}}}`,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get/parameters/0/schema",
+				Message:       "enum value dog was removed",
+				BreaksClients: true,
+			}},
+		},
+		"widened response enum": {
+			oldPaths: `"/pets": {"get": {"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"enum": ["cat"]}}}}}}}`,
+			newPaths: `"/pets": {"get": {"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"enum": ["cat", "dog"]}}}}}}}`,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get/responses/200/content/application~1json/schema",
+				Message:       "enum value dog was added",
+				BreaksClients: true,
+			}},
+		},
+		"removed response": {
+			oldPaths: `"/pets": {"get": {"responses": {"200": {"description": "OK"}, "404": {"description": "Not found"}}}}`,
+			newPaths: `"/pets": {"get": {"responses": {"200": {"description": "OK"}}}}`,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get/responses/404",
+				Message:       "response 404 was removed",
+				BreaksServers: true,
+			}},
+		},
+		"tightened request constraint": {
+			oldPaths: `"/pets": {"get": {"parameters": [{"name": "name", "in": "query", "schema": {"type": "string", "maxLength": 10}}], "responses": {}}}`,
+			newPaths: `"/pets": {"get": {"parameters": [{"name": "name", "in": "query", "schema": {"type": "string", "maxLength": 5}}], "responses": {}}}`,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get/parameters/0/schema",
+				Message:       "maxLength changed from 10 to 5",
+				BreaksClients: true,
+			}},
+		},
+		"tightened response constraint": {
+			oldPaths: `"/pets": {"get": {"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "string", "maxLength": 10}}}}}}}`,
+			newPaths: `"/pets": {"get": {"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "string", "maxLength": 5}}}}}}}`,
+			expected: Changes{{
+				Pointer:       "#/paths/~1pets/get/responses/200/content/application~1json/schema",
+				Message:       "maxLength changed from 10 to 5",
+				BreaksServers: true,
+			}},
+		},
+		"changed type of a shared schema": {
+			oldPaths: `"/pets": {"post": {
+				"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+				"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+			}}`,
+			newPaths: `"/pets": {"post": {
+				"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+				"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+			}}`,
+			oldSchemas: `"Pet": {"type": "object", "properties": {"id": {"type": "integer"}}}`,
+			newSchemas: `"Pet": {"type": "object", "properties": {"id": {"type": "string"}}}`,
+			expected: Changes{{
+				Pointer:       "#/components/schemas/Pet/properties/id",
+				Message:       "type changed from integer to string",
+				BreaksClients: true,
+				BreaksServers: true,
+			}},
+		},
+		"recursive schema": {
+			oldPaths:   `"/nodes": {"get": {"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}}}}}`,
+			newPaths:   `"/nodes": {"get": {"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}}}}}`,
+			oldSchemas: `"Node": {"type": "object", "properties": {"name": {"type": "string"}, "children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}`,
+			newSchemas: `"Node": {"type": "object", "properties": {"name": {"type": "string", "minLength": 1}, "children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}`,
+			expected: Changes{{
+				Pointer:       "#/components/schemas/Node/properties/name",
+				Message:       "minLength 1 was added",
+				BreaksServers: true,
+			}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			old, err := loadOpenAPIDocument(diffSpec(test.oldPaths, test.oldSchemas), ".")
+			require.NoError(t, err)
+			new, err := loadOpenAPIDocument(diffSpec(test.newPaths, test.newSchemas), ".")
+			require.NoError(t, err)
+
+			changes := DiffSpecs(old, new)
+			assert.Equal(t, test.expected, changes)
+			assert.Equal(t, test.expected.Breaking(), changes.Breaking())
+		})
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	tests := map[string]struct {
+		change   Change
+		expected string
+	}{
+		"non-breaking": {
+			change:   Change{Pointer: "#/paths/~1pets/get", Message: "operation list-pets was deprecated"},
+			expected: "#/paths/~1pets/get: non-breaking: operation list-pets was deprecated",
+		},
+		"breaking for clients": {
+			change:   Change{Pointer: "#/paths/~1pets/get", Message: "operation list-pets was removed", BreaksClients: true},
+			expected: "#/paths/~1pets/get: breaking for clients: operation list-pets was removed",
+		},
+		"breaking for both": {
+			change:   Change{Pointer: "#/components/schemas/Pet", Message: "type changed from integer to string", BreaksClients: true, BreaksServers: true},
+			expected: "#/components/schemas/Pet: breaking for clients and servers: type changed from integer to string",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.change.String())
+		})
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")
+	require.NoError(t, os.WriteFile(oldPath, diffSpec(`"/pets": {"get": {"operationId": "list-pets", "responses": {}}}`, ""), 0o644))
+	require.NoError(t, os.WriteFile(newPath, diffSpec("", ""), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := runDiff([]string{"-format", "json", oldPath, newPath}, &stdout, &stderr)
+	assert.Equal(t, 1, code, stderr.String())
+	var changes Changes
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &changes))
+	assert.Equal(t, Changes{{
+		Pointer:       "#/paths/~1pets/get",
+		Message:       "operation list-pets was removed",
+		BreaksClients: true,
+	}}, changes)
+
+	stdout.Reset()
+	assert.Equal(t, 1, runDiff([]string{newPath, oldPath}, &stdout, &stderr))
+	assert.Equal(t, "#/paths/~1pets/get: breaking for servers: operation list-pets was added\n", stdout.String())
+
+	assert.Equal(t, 2, runDiff([]string{oldPath}, &stdout, &stderr))
+}
+
+func TestRunDiffRevision(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		command := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		command.Dir = dir
+		output, err := command.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	specs := filepath.Join(dir, "specs")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "common"), 0o755))
+	require.NoError(t, os.MkdirAll(specs, 0o755))
+	spec := filepath.Join(specs, "openapi.json")
+	require.NoError(t, os.WriteFile(spec, diffSpec(
+		`"/pets": {"get": {"operationId": "list-pets", "responses": {"200": {"description": "The pets.", "content": {"application/json": {"schema": {"$ref": "../common/pet.json"}}}}}}}`, "",
+	), 0o644))
+	pet := filepath.Join(dir, "common", "pet.json")
+	require.NoError(t, os.WriteFile(pet, []byte(`{"type": "string", "enum": ["cat", "dog"]}`), 0o644))
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add pets")
+
+	// The referenced file changes in the working tree only.
+	require.NoError(t, os.WriteFile(pet, []byte(`{"type": "string", "enum": ["cat", "dog", "fish"]}`), 0o644))
+	var stdout, stderr bytes.Buffer
+	code := runDiff([]string{"-rev", "HEAD", spec}, &stdout, &stderr)
+	assert.Equal(t, 1, code, stderr.String())
+	assert.Contains(t, stdout.String(), "breaking for clients")
+	assert.Contains(t, stdout.String(), "fish")
+
+	stdout.Reset()
+	git("commit", "-q", "-a", "-m", "Add fish")
+	assert.Equal(t, 0, runDiff([]string{"-rev", "HEAD", spec}, &stdout, &stderr), stderr.String())
+	assert.Empty(t, stdout.String())
+}
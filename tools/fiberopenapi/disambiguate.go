@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Gives unique names to the types of the models. Models named by the
+// specification (components and referenced schemas) keep their names, while
+// the rest of the colliding models are prefixed with the name of the model
+// that contains them. For example the inline status property of an order
+// becomes OrderStatus when there is a status component. Renaming is repeated
+// until there are no collisions, or an error listing the locations of the
+// colliding schemas is returned when they cannot be told apart.
+//
+// The names that the rest of the generated code declares, the reserved ones
+// and the constructors of the models, are taken too. The models that would
+// declare them are renamed even if they are named by the specification, with
+// a warning, as Model is appended to their names.
+func disambiguate(models []Model, reserved []string) (Diagnostics, error) {
+	var diagnostics Diagnostics
+	for {
+		var names []string
+		collisions := map[string][]*baseModel{}
+		// The models that declare a name besides their type, by name.
+		declared := map[string][]*baseModel{}
+		var declaredNames []string
+		for _, model := range models {
+			for _, modelType := range model.Types() {
+				m := modelType.(hasBase).base()
+				if _, ok := collisions[m.name]; !ok {
+					names = append(names, m.name)
+				}
+				collisions[m.name] = append(collisions[m.name], m)
+				if object, ok := modelType.(*objectModel); ok {
+					for _, name := range object.constructorNames() {
+						if _, ok := declared[name]; !ok {
+							declaredNames = append(declaredNames, name)
+						}
+						declared[name] = append(declared[name], m)
+					}
+				}
+			}
+		}
+		renamed := false
+		// Renames a model whose name is taken by the generated code, once per
+		// round as its constructor names change with it.
+		forced := map[*baseModel]bool{}
+		force := func(m *baseModel, taken string) {
+			if forced[m] {
+				return
+			}
+			forced[m] = true
+			renamed = true
+			if !m.named && m.parent != nil {
+				m.name = m.parent.Name() + m.name
+				return
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarning,
+				Message: fmt.Sprintf(
+					"type %s is renamed %sModel, as the generated code declares %s", m.name, m.name, taken,
+				),
+				Pointer: m.location,
+			})
+			m.name += "Model"
+		}
+		var errs []string
+		for _, name := range names {
+			colliding := collisions[name]
+			if slices.Contains(reserved, name) || len(declared[name]) > 0 {
+				for _, m := range colliding {
+					force(m, name)
+				}
+				continue
+			}
+			if len(colliding) < 2 {
+				continue
+			}
+			var named, unnamed []*baseModel
+			for _, m := range colliding {
+				if m.named {
+					named = append(named, m)
+				} else {
+					unnamed = append(unnamed, m)
+				}
+			}
+			// When all colliding models are unnamed, the first one keeps its
+			// name unless it can be prefixed too.
+			if len(named) == 0 && unnamed[0].parent == nil {
+				unnamed = unnamed[1:]
+			}
+			if len(named) > 1 || !canBePrefixed(unnamed) {
+				errs = append(errs, collisionError(name, colliding))
+				continue
+			}
+			for _, m := range unnamed {
+				m.name = m.parent.Name() + m.name
+				renamed = true
+			}
+		}
+		if len(errs) > 0 {
+			return diagnostics, fmt.Errorf("type name collisions:\n%s", strings.Join(errs, "\n"))
+		}
+		if renamed {
+			continue
+		}
+		// The constructors of the models cannot declare reserved names, nor
+		// the names of the constructors of other models.
+		for _, name := range declaredNames {
+			declaring := declared[name]
+			if !slices.Contains(reserved, name) {
+				declaring = declaring[1:]
+			}
+			for _, m := range declaring {
+				force(m, name)
+			}
+		}
+		if !renamed {
+			return diagnostics, nil
+		}
+	}
+}
+
+// Whether all the models have a parent that can prefix their names, and the
+// prefixed names will be different.
+func canBePrefixed(models []*baseModel) bool {
+	parents := map[Model]bool{}
+	for _, m := range models {
+		if m.parent == nil || parents[m.parent] {
+			return false
+		}
+		parents[m.parent] = true
+	}
+	return true
+}
+
+func collisionError(name string, colliding []*baseModel) string {
+	locations := make([]string, len(colliding))
+	for i, m := range colliding {
+		locations[i] = m.location
+	}
+	return fmt.Sprintf("\t%s: %s", name, strings.Join(locations, ", "))
+}
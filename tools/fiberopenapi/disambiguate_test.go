@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisambiguate(t *testing.T) {
+	testCases := map[string]struct {
+		schemas          string
+		expectedNames    []string
+		expectedWarnings []string
+		expectedErr      string
+	}{
+		"inline property colliding with a component": {
+			schemas: `{
+				"status": {"type": "string"},
+				"order": {
+					"type": "object",
+					"properties": {"status": {"type": "integer"}}
+				}
+			}`,
+			expectedNames: []string{"Status", "Order", "OrderStatus"},
+		},
+		"inline properties colliding with each other": {
+			schemas: `{
+				"order": {
+					"type": "object",
+					"properties": {"status": {"type": "integer"}}
+				},
+				"task": {
+					"type": "object",
+					"properties": {"status": {"type": "string"}}
+				}
+			}`,
+			expectedNames: []string{"Order", "OrderStatus", "Task", "TaskStatus"},
+		},
+		"components colliding with each other": {
+			schemas: `{
+				"pet-status": {"type": "string"},
+				"PetStatus": {"type": "string"}
+			}`,
+			expectedErr: "PetStatus: #/components/schemas/pet-status, #/components/schemas/PetStatus",
+		},
+		"component colliding with the runtime": {
+			schemas: `{
+				"Decimal": {"type": "string"},
+				"order": {
+					"type": "object",
+					"properties": {"null": {"type": "string"}}
+				}
+			}`,
+			expectedNames: []string{"DecimalModel", "Order", "OrderNull"},
+			expectedWarnings: []string{
+				"#/components/schemas/Decimal: type Decimal is renamed DecimalModel, as the generated code declares Decimal",
+			},
+		},
+		"component colliding with a constructor": {
+			schemas: `{
+				"pet": {
+					"type": "object",
+					"properties": {"name": {"type": "string"}}
+				},
+				"PetOption": {"type": "string"},
+				"NewPet": {"type": "string"}
+			}`,
+			expectedNames: []string{"Pet", "Name", "PetOptionModel", "NewPetModel"},
+			expectedWarnings: []string{
+				"#/components/schemas/PetOption: type PetOption is renamed PetOptionModel, as the generated code declares PetOption",
+				"#/components/schemas/NewPet: type NewPet is renamed NewPetModel, as the generated code declares NewPet",
+			},
+		},
+		"constructor colliding with the runtime": {
+			schemas: `{
+				"MaxLengthError": {
+					"type": "object",
+					"properties": {"got": {"type": "integer"}}
+				}
+			}`,
+			expectedNames: []string{"MaxLengthErrorModel", "Got"},
+			expectedWarnings: []string{
+				"#/components/schemas/MaxLengthError: type MaxLengthError is renamed MaxLengthErrorModel, as the generated code declares NewMaxLengthError",
+			},
+		},
+	}
+	reserved, err := declaredNames([]byte(modelsFile))
+	require.NoError(t, err)
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec, err := loadOpenAPIDocument([]byte(`{
+				"openapi": "3.1.0",
+				"info": {"title": "Collisions", "version": "1.0.0"},
+				"paths": {},
+				"components": {"schemas": `+tc.schemas+`}
+			}`), ".")
+			require.NoError(t, err)
+			b := newModelBuilder(spec.Model.Components)
+			models := b.extractModelsFromComponents(spec.Model.Components)
+			diagnostics, err := disambiguate(models, reserved)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, model := range models {
+				for _, modelType := range model.Types() {
+					names = append(names, modelType.Name())
+				}
+			}
+			assert.Equal(t, tc.expectedNames, names)
+			var warnings []string
+			for _, diagnostic := range diagnostics {
+				warnings = append(warnings, diagnostic.Error())
+			}
+			assert.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats paragraphs as a Go doc comment, with each line indented with indent.
+// Empty paragraphs are skipped, so there is no comment if all of them are.
+func docComment(indent string, paragraphs ...string) string {
+	var lines []string
+	for _, paragraph := range paragraphs {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, indent+"//")
+		}
+		for _, line := range strings.Split(paragraph, "\n") {
+			lines = append(lines, strings.TrimRight(indent+"// "+line, " "))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// The paragraphs that document a schema: its description, or a deprecation
+// notice with it, its examples and its external documentation.
+func schemaDoc(schema *base.Schema) []string {
+	if schema == nil {
+		return nil
+	}
+	description := schema.Description
+	if schema.Deprecated != nil && *schema.Deprecated {
+		if description != "" {
+			description = "Deprecated: " + description
+		} else {
+			description = "Deprecated"
+		}
+	}
+	return []string{
+		description,
+		examplesDoc(schema.Example, schema.Examples),
+		externalDocsDoc(schema.ExternalDocs),
+	}
+}
+
+// Documents example values as JSON, like "Example: 42".
+func examplesDoc(example *yaml.Node, examples []*yaml.Node) string {
+	if example != nil {
+		examples = append([]*yaml.Node{example}, examples...)
+	}
+	var values []string
+	for _, node := range examples {
+		if value := exampleJSON(node); value != "" {
+			values = append(values, value)
+		}
+	}
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return "Example: " + values[0]
+	}
+	return "Examples:\n  - " + strings.Join(values, "\n  - ")
+}
+
+// An example value as JSON, or nothing if it cannot be encoded.
+func exampleJSON(node *yaml.Node) string {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Links to external documentation, like "See the guide at https://...".
+func externalDocsDoc(docs *base.ExternalDoc) string {
+	if docs == nil || docs.URL == "" {
+		return ""
+	}
+	if docs.Description != "" {
+		return fmt.Sprintf("%s: %s", strings.TrimSuffix(docs.Description, "."), docs.URL)
+	}
+	return "See " + docs.URL
+}
+
+// Documents security requirements, which are alternatives, like
+// "api_key or oauth (read:pets)". An empty requirement makes the security
+// optional.
+func securityDoc(requirements []*base.SecurityRequirement) string {
+	var alternatives []string
+	for _, requirement := range requirements {
+		if requirement.ContainsEmptyRequirement || requirement.Requirements.Len() == 0 {
+			alternatives = append(alternatives, "none")
+			continue
+		}
+		var schemes []string
+		for pair := requirement.Requirements.First(); pair != nil; pair = pair.Next() {
+			scheme := pair.Key()
+			if len(pair.Value()) > 0 {
+				scheme += " (" + strings.Join(pair.Value(), ", ") + ")"
+			}
+			schemes = append(schemes, scheme)
+		}
+		alternatives = append(alternatives, strings.Join(schemes, " and "))
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// Documents a method of the handlers interface, or of the client, with the
+// summary, description, parameters, tags, security and external documentation
+// of its operation.
+func operationDoc(indent string, operation Operation) string {
+	var parameters []string
+	if operation.RequestBodyDescription != "" {
+		parameters = append(parameters, "  - body: "+operation.RequestBodyDescription)
+	}
+	for _, parameter := range operation.Parameters {
+		if parameter.Description != "" {
+			parameters = append(parameters,
+				fmt.Sprintf("  - %s: %s", parameter.Name, parameter.Description),
+			)
+		}
+	}
+	var parametersDoc string
+	if len(parameters) > 0 {
+		parametersDoc = "Parameters:\n" + strings.Join(parameters, "\n")
+	}
+	var metadata []string
+	if len(operation.Tags) > 0 {
+		metadata = append(metadata, "Tags: "+strings.Join(operation.Tags, ", ")+".")
+	}
+	if operation.Security != "" {
+		metadata = append(metadata, "Security: "+operation.Security+".")
+	}
+	var deprecated string
+	switch {
+	case operation.Deprecated && operation.Sunset != "":
+		deprecated = "Deprecated: the operation will be removed on " + operation.Sunset + "."
+	case operation.Deprecated:
+		deprecated = "Deprecated: the operation is deprecated."
+	}
+	return docComment(indent,
+		operation.Summary,
+		operation.Description,
+		parametersDoc,
+		strings.Join(metadata, " "),
+		operation.ExternalDocs,
+		deprecated,
+	)
+}
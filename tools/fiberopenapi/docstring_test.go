@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDocComment(t *testing.T) {
+	assert.Equal(t, "", docComment("\t", "", " "))
+	assert.Equal(t, "\t// Find a pet\n\t//\n\t// Returns it.\n\t// Or 404.\n",
+		docComment("\t", "Find a pet", "", "Returns it.\nOr 404.\n"),
+	)
+}
+
+func TestSchemaDoc(t *testing.T) {
+	deprecated := true
+	schema := &base.Schema{
+		Description:  "A pet.",
+		Deprecated:   &deprecated,
+		Example:      &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "42"},
+		Examples:     []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: "x"}},
+		ExternalDocs: &base.ExternalDoc{Description: "The guide.", URL: "https://example.com"},
+	}
+	assert.Equal(t, "// Deprecated: A pet.\n"+
+		"//\n"+
+		"// Examples:\n"+
+		"//   - 42\n"+
+		"//   - \"x\"\n"+
+		"//\n"+
+		"// The guide: https://example.com\n",
+		docComment("", schemaDoc(schema)...),
+	)
+}
+
+func TestOperationDoc(t *testing.T) {
+	scopes := orderedmap.New[string, []string]()
+	scopes.Set("oauth", []string{"read:pets", "write:pets"})
+	keys := orderedmap.New[string, []string]()
+	keys.Set("api_key", nil)
+	operation := Operation{
+		Name:                   "UpdatePet",
+		Summary:                "Update a pet",
+		RequestBodyDescription: "The new pet.",
+		Parameters: []Parameter{
+			{Name: "petId", Description: "ID of the pet. Example: 1"},
+			{Name: "debug"},
+		},
+		Tags: []string{"pet", "store"},
+		Security: securityDoc([]*base.SecurityRequirement{
+			{Requirements: keys}, {Requirements: scopes},
+		}),
+		ExternalDocs: "See https://example.com",
+		Deprecated:   true,
+		Sunset:       "Thu, 31 Dec 2026 00:00:00 GMT",
+	}
+	assert.Equal(t, "\t// Update a pet\n"+
+		"\t//\n"+
+		"\t// Parameters:\n"+
+		"\t//   - body: The new pet.\n"+
+		"\t//   - petId: ID of the pet. Example: 1\n"+
+		"\t//\n"+
+		"\t// Tags: pet, store. Security: api_key or oauth (read:pets, write:pets).\n"+
+		"\t//\n"+
+		"\t// See https://example.com\n"+
+		"\t//\n"+
+		"\t// Deprecated: the operation will be removed on Thu, 31 Dec 2026 00:00:00 GMT.\n",
+		operationDoc("\t", operation),
+	)
+}
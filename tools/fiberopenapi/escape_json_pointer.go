@@ -0,0 +1,9 @@
+package main
+
+import "strings"
+
+// Escapes a key to be used as a JSON pointer segment, as described in
+// https://datatracker.ietf.org/doc/html/rfc6901#section-3.
+func EscapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
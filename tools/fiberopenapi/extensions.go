@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// Vendor extensions that control the generated code.
+const (
+	// Name of a type, a struct field, a method or a parameter.
+	ExtensionGoName = "x-go-name"
+	// Existing Go type to use instead of generating one.
+	ExtensionGoType = "x-go-type"
+	// Import of the package of the x-go-type. Either an import path or an
+	// object with the path and the name of the import.
+	ExtensionGoTypeImport = "x-go-type-import"
+	// Whether a struct field is ignored when marshalling to JSON.
+	ExtensionGoJSONIgnore = "x-go-json-ignore"
+	// Whether a struct field is omitted when empty, by default optional
+	// properties are omitted and required ones are not.
+	ExtensionOmitEmpty = "x-omitempty"
+	// Whether an optional property is not a pointer.
+	ExtensionGoTypeSkipOptionalPointer = "x-go-type-skip-optional-pointer"
+	// Date, like 2025-12-31, after which an operation is removed. It is sent
+	// in the Sunset response header.
+	ExtensionSunset = "x-sunset"
+	// Whether an operation is internal, which the filters of the
+	// configuration can select to leave it out of the generated code.
+	ExtensionInternal = "x-internal"
+)
+
+// Decodes the value of a vendor extension into v. Returns whether the
+// extension is present and could be decoded.
+func GetExtension(extensions *orderedmap.Map[string, *yaml.Node], name string, v any) bool {
+	if extensions == nil {
+		return false
+	}
+	node := extensions.GetOrZero(name)
+	if node == nil {
+		return false
+	}
+	return node.Decode(v) == nil
+}
+
+// Whether a boolean vendor extension is present and true.
+func HasExtension(extensions *orderedmap.Map[string, *yaml.Node], name string) bool {
+	var value bool
+	return GetExtension(extensions, name, &value) && value
+}
+
+type goTypeImport struct {
+	Path string `yaml:"path"`
+	Name string `yaml:"name"`
+}
+
+// The import spec of the x-go-type-import extension, for example
+// `"github.com/shopspring/decimal"` or `money "example.com/money/v2"`.
+func GetGoTypeImport(extensions *orderedmap.Map[string, *yaml.Node]) string {
+	var path string
+	if GetExtension(extensions, ExtensionGoTypeImport, &path) {
+		return fmt.Sprintf("%q", path)
+	}
+	var object goTypeImport
+	if !GetExtension(extensions, ExtensionGoTypeImport, &object) || object.Path == "" {
+		return ""
+	}
+	if object.Name == "" {
+		return fmt.Sprintf("%q", object.Path)
+	}
+	return fmt.Sprintf("%s %q", object.Name, object.Path)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	_ "embed"
+	"text/template"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+//go:embed base_client.go
+var clientFile string
+
+// Generates a client with a method for each operation, that takes the same
+// parameters as the handlers and returns the response decoded as the model of
+// its status code. It uses the models generated by GenerateModels.
+func GenerateClient(spec *libopenapi.DocumentModel[v3.Document], packageName, outputPath, typeName string, models ModelOptions, layout Layout, templates *template.Template, output *Output) error {
+	files, err := generateClient(spec, packageName, outputPath, typeName, models, layout, templates)
+	if err != nil {
+		return err
+	}
+	return files.Write(output)
+}
+
+// The names declared by the generated client, that the models generated in
+// the same package cannot use.
+func ClientNames(spec *libopenapi.DocumentModel[v3.Document], typeName string, templates *template.Template) ([]string, error) {
+	files, err := generateClient(spec, "client", "client.go", typeName, ModelOptions{}, Layout{}, templates)
+	if err != nil {
+		return nil, err
+	}
+	src, err := files.main.Source()
+	if err != nil {
+		return nil, err
+	}
+	return declaredNames(src)
+}
+
+func generateClient(spec *libopenapi.DocumentModel[v3.Document], packageName, outputPath, typeName string, models ModelOptions, layout Layout, templates *template.Template) (*layoutFiles, error) {
+	g := &Generator{Package: packageName, Qualifier: layout.Qualifier}
+
+	operations, diagnostics := ExtractOperations(spec, models)
+	if err := diagnostics.Err(); err != nil {
+		return nil, err
+	}
+	// Import the packages of the types that are existing Go types.
+	for _, operation := range operations {
+		g.Import(operation.Imports...)
+	}
+	g.Printf("%s", baseCode(clientFile))
+	files := newLayoutFiles(layout, outputPath, g)
+
+	data := newTemplateData(typeName, operations)
+	if err := g.Execute(templates, "client.tmpl", data); err != nil {
+		return nil, err
+	}
+	for _, operation := range data.Operations {
+		if err := files.For(operation.Operation).Execute(templates, "client_operation.tmpl", operation); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -1,123 +1,88 @@
 package main
 
 import (
-	"fmt"
-	"os"
-	"strings"
+	"strconv"
+	"text/template"
 
 	"github.com/pb33f/libopenapi"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 )
 
-func GenerateHandlers(spec *libopenapi.DocumentModel[v3.Document], packagePath, outputPath, typeName string) error {
-	g := &Generator{}
+// Declares an interface for the handlers of each tag.
+const InterfacesByTag = "tag"
 
-	// Get the package name and generate file header.
-	packageName, err := LoadPackageName(packagePath)
+// Options that change what is generated with the handlers.
+type HandlersOptions struct {
+	// Embed the specification, written by GenerateSpec, in the generated
+	// package, to serve it with an option of the generated Add function.
+	EmbedSpec bool
+	// Declare an interface for the operations of each tag, like
+	// GameplayHandlers, that the handlers interface embeds, so that the
+	// handlers of each tag can be implemented apart.
+	TagInterfaces bool
+	// The options of the models that the operations use.
+	Models ModelOptions
+}
+
+func GenerateHandlers(spec *libopenapi.DocumentModel[v3.Document], packageName, outputPath, typeName string, options HandlersOptions, layout Layout, templates *template.Template, output *Output) error {
+	files, err := generateHandlers(spec, packageName, outputPath, typeName, options, layout, templates)
 	if err != nil {
 		return err
 	}
-	g.Printf(`package %s
-
-// Code generated by "fiberopenapi %s"; DO NOT EDIT.
-
-import (
-	"encoding/json"
+	return files.Write(output)
+}
 
-	"github.com/gofiber/fiber/v2"
-)
-`,
-		packageName,
-		strings.Join(os.Args[1:], " "),
-	)
+// The names declared by the generated handlers, that the models generated in
+// the same package cannot use.
+func HandlersNames(spec *libopenapi.DocumentModel[v3.Document], typeName string, options HandlersOptions, templates *template.Template) ([]string, error) {
+	files, err := generateHandlers(spec, "handlers", "handlers.go", typeName, options, Layout{}, templates)
+	if err != nil {
+		return nil, err
+	}
+	src, err := files.main.Source()
+	if err != nil {
+		return nil, err
+	}
+	return declaredNames(src)
+}
 
-	operations := ExtractOperations(spec)
+func generateHandlers(spec *libopenapi.DocumentModel[v3.Document], packageName, outputPath, typeName string, options HandlersOptions, layout Layout, templates *template.Template) (*layoutFiles, error) {
+	g := &Generator{Package: packageName, Qualifier: layout.Qualifier}
 
-	// Generate the ValidatedHandlers interface.
-	g.Printf(`
-// Implement this interface.
-type %s interface {
-`,
-		typeName,
-	)
-	for _, operation := range operations {
-		g.Printf("\t%s(c *fiber.Ctx", operation.Name)
-		if operation.RequestBody != "" {
-			g.Printf(", body %s", operation.RequestBody)
-		}
-		for _, parameter := range operation.Parameters {
-			g.Printf(", %s %s", parameter.Name, parameter.Type)
-		}
-		// TODO response types
-		g.Println(") error")
+	operations, diagnostics := ExtractOperations(spec, options.Models)
+	if err := diagnostics.Err(); err != nil {
+		return nil, err
 	}
-	g.Println("}")
-
-	// Generate the raw rawHandlers interface.
-	g.Println("\ntype rawHandlers interface {")
+	// Import the packages of the types that are existing Go types.
 	for _, operation := range operations {
-		g.Printf("\t%s(c *fiber.Ctx) error\n", ToPascalCase(operation.Name))
+		g.Import(operation.Imports...)
 	}
-	g.Println("}")
-
-	// Generate AddHandlers function.
-	g.Println("\nfunc addRawHandlers(app *fiber.App, h rawHandlers) {")
-	for _, operation := range operations {
-		g.Printf("\tapp.%s(\"%s\", h.%s)\n",
-			operation.Method, operation.Path, operation.Name,
-		)
+	g.Import(strconv.Quote(fiberImport))
+	if options.EmbedSpec {
+		g.Import(`_ "embed"`)
 	}
-	g.Println("}")
+	files := newLayoutFiles(layout, outputPath, g)
 
-	// Generate the wrapper that implements the raw handlers interface.
-	g.Printf(`
-type validated%s struct {
-	validated %s
-}
-
-func Add%s(app *fiber.App, h %s) {
-	addRawHandlers(app, &validated%s{h})
-}`+"\n",
-		typeName, typeName, typeName, typeName, typeName,
-	)
-	for _, operation := range operations {
-		g.Printf("\nfunc (h *validated%s) %s(c *fiber.Ctx) error {",
-			typeName, operation.Name,
-		)
-		if operation.RequestBody != "" {
-			// The request body type implements json.Unmarshaler and will be
-			// validated when unmarshalled.
-			g.Printf(`
-	var body %s
-	if err := json.Unmarshal(c.Body(), &body); err != nil {
-		return err
-	}`,
-				operation.RequestBody,
-			)
-		}
-		for _, parameter := range operation.Parameters {
-			// The parameter type implements json.Unmarshaler and will be
-			// validated when unmarshalled.
-			g.Printf(`
-	var %s %s
-	if err := json.Unmarshal([]byte(c.Params("%s")), &%s); err != nil {
-		return err
-	}`,
-				parameter.Name, parameter.Type, parameter.Name, parameter.Name,
-			)
-		}
-		g.Printf("\n\treturn h.validated.%s(c", operation.Name)
-		if operation.RequestBody != "" {
-			g.Printf(", body")
-		}
-		for _, parameter := range operation.Parameters {
-			g.Printf(", %s", parameter.Name)
+	data := newTemplateData(typeName, operations)
+	data.EmbedSpec = options.EmbedSpec
+	if options.TagInterfaces {
+		data.Interfaces = tagInterfaces(typeName, data.Operations)
+	}
+	data.SpecJSONFile, data.SpecYAMLFile, data.SpecReferenceFile = specJSONFile, specYAMLFile, specReferenceFile
+	// The interfaces, the operations table and the wrapper that validates the
+	// requests before calling the handlers.
+	if err := g.Execute(templates, "handlers.tmpl", data); err != nil {
+		return nil, err
+	}
+	for _, operation := range data.Operations {
+		if err := files.For(operation.Operation).Execute(templates, "handler.tmpl", operation); err != nil {
+			return nil, err
 		}
-		g.Println(")\n}")
 	}
-	// Write the generated code back to main.go
-	if err := g.WriteFile(outputPath); err != nil {
-		return fmt.Errorf("cannot write generated code: %w", err)
+	if options.EmbedSpec {
+		if err := g.Execute(templates, "specification.tmpl", data); err != nil {
+			return nil, err
+		}
 	}
-	return nil
+	return files, nil
 }
@@ -3,8 +3,7 @@ package main
 import (
 	_ "embed"
 	"fmt"
-	"os"
-	"strings"
+	"text/template"
 
 	"github.com/pb33f/libopenapi"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -13,34 +12,70 @@ import (
 //go:embed base_models.go
 var modelsFile string
 
-func GenerateModels(spec *libopenapi.DocumentModel[v3.Document], packagePath, outputPath string) error {
-	g := &Generator{}
-
-	// Get the package name and generate file header.
-	packageName, err := LoadPackageName(packagePath)
+func GenerateModels(spec *libopenapi.DocumentModel[v3.Document], packageName, outputPath string, options ModelOptions, layout Layout, templates *template.Template, output *Output) error {
+	g, err := generateModels(spec, packageName, options, layout, templates)
 	if err != nil {
 		return err
 	}
+	// Write the generated code.
+	if err := g.WriteFile(output, outputPath); err != nil {
+		return fmt.Errorf("cannot write generated code: %w", err)
+	}
+	return nil
+}
 
-	g.Printf(`package %s
-
-// Code generated by "fiberopenapi %s"; DO NOT EDIT.
-
-%s
-`,
-		packageName,
-		strings.Join(os.Args[1:], " "),
-		strings.TrimPrefix(modelsFile, "package main\n\n"),
-	)
+// The names declared by the generated models, that the code generated in other
+// packages qualifies.
+func ModelNames(spec *libopenapi.DocumentModel[v3.Document], options ModelOptions, templates *template.Template) ([]string, error) {
+	g, err := generateModels(spec, "models", options, Layout{SharedRuntime: true}, templates)
+	if err != nil {
+		return nil, err
+	}
+	src, err := g.Source()
+	if err != nil {
+		return nil, err
+	}
+	return declaredNames(src)
+}
 
-	modelTypes := ExtractModelTypesFromDocument(spec)
+func generateModels(spec *libopenapi.DocumentModel[v3.Document], packageName string, options ModelOptions, layout Layout, templates *template.Template) (g *Generator, err error) {
+	// Values of the specification that cannot be written as Go fail the
+	// generation too.
+	defer recoverDiagnostic(&err)
+	g = &Generator{Package: packageName, Qualifier: layout.Qualifier}
 
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, options)
+	if err := diagnostics.Err(); err != nil {
+		return nil, err
+	}
+	// Import the packages of the types that are existing Go types.
+	for _, modelType := range modelTypes {
+		g.Import(modelType.Imports()...)
+	}
+	// The runtime helpers are in the models, unless they are in their own
+	// package, which the models import instead.
+	if !layout.SharedRuntime {
+		g.Printf("%s", baseCode(modelsFile))
+	}
 	for _, modelType := range modelTypes {
-		g.Printf("\n%stype %s %s\n", modelType.Docstring(), modelType.Name(), modelType.Definition())
+		if err := g.Execute(templates, "model.tmpl", newModelData(modelType)); err != nil {
+			return nil, err
+		}
 	}
+	return g, nil
+}
 
-	// Write the generated code.
-	if err := g.WriteFile(outputPath); err != nil {
+// Generates the runtime helpers of the models, like ValidationError or Null, in
+// their own package, for several packages of models to share them. Their
+// unexported helpers are exported, for the generated code to use them.
+func GenerateRuntime(packageName, outputPath string, output *Output) error {
+	runtime, err := exportDeclarations([]byte(modelsFile))
+	if err != nil {
+		return fmt.Errorf("cannot export runtime helpers: %w", err)
+	}
+	g := &Generator{Package: packageName}
+	g.Printf("%s", baseCode(string(runtime)))
+	if err := g.WriteFile(output, outputPath); err != nil {
 		return fmt.Errorf("cannot write generated code: %w", err)
 	}
 	return nil
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// A regular expression that matches the request paths of a path template,
+// where each parameter is a non-empty part of a segment.
+func routePattern(pathTemplate string) string {
+	var pattern string
+	last := 0
+	for _, match := range openApiPathParamPattern.FindAllStringIndex(pathTemplate, -1) {
+		pattern += regexp.QuoteMeta(pathTemplate[last:match[0]]) + "[^/]+"
+		last = match[1]
+	}
+	return "^" + pattern + regexp.QuoteMeta(pathTemplate[last:]) + "$"
+}
+
+// A []string literal, or nil.
+func stringsLiteral(values []string) string {
+	if values == nil {
+		return "nil"
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// A []map[string][]string literal of security requirements, or nil.
+func securityLiteral(requirements []map[string][]string) string {
+	if requirements == nil {
+		return "nil"
+	}
+	alternatives := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		schemes := make([]string, 0, len(requirement))
+		for scheme, scopes := range requirement {
+			schemes = append(schemes, fmt.Sprintf("%q: %s", scheme, stringsLiteral(scopes)))
+		}
+		slices.Sort(schemes)
+		alternatives[i] = "{" + strings.Join(schemes, ", ") + "}"
+	}
+	return "[]map[string][]string{" + strings.Join(alternatives, ", ") + "}"
+}
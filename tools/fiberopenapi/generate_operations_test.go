@@ -0,0 +1,54 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutePattern(t *testing.T) {
+	tests := map[string]struct {
+		pathTemplate string
+		matches      []string
+		mismatches   []string
+	}{
+		"static": {
+			pathTemplate: "/board",
+			matches:      []string{"/board"},
+			mismatches:   []string{"/board/1", "/boards", "/"},
+		},
+		"parameters": {
+			pathTemplate: "/board/{row}/{column}",
+			matches:      []string{"/board/1/2", "/board/:row/:column"},
+			mismatches:   []string{"/board/1", "/board/1/2/3", "/board//2"},
+		},
+		"parameters in a segment": {
+			pathTemplate: "/files/{name}.{ext}",
+			matches:      []string{"/files/a.txt", "/files/:name.:ext"},
+			mismatches:   []string{"/files/a", "/files/atxt"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pattern := regexp.MustCompile(routePattern(test.pathTemplate))
+			for _, path := range test.matches {
+				assert.True(t, pattern.MatchString(path), path)
+			}
+			for _, path := range test.mismatches {
+				assert.False(t, pattern.MatchString(path), path)
+			}
+		})
+	}
+}
+
+func TestSecurityLiteral(t *testing.T) {
+	assert.Equal(t, "nil", securityLiteral(nil))
+	assert.Equal(t,
+		`[]map[string][]string{{"api_key": nil, "oauth": []string{"read", "write"}}, {}}`,
+		securityLiteral([]map[string][]string{
+			{"oauth": {"read", "write"}, "api_key": nil},
+			{},
+		}),
+	)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/bundler"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/json"
+	"gopkg.in/yaml.v3"
+)
+
+// The files written by GenerateSpec, embedded by the generated handlers.
+const (
+	specJSONFile      = "openapi.json"
+	specYAMLFile      = "openapi.yaml"
+	specReferenceFile = "openapi.html"
+)
+
+// Writes the specification next to the generated handlers, as JSON, as YAML
+// and as a reference page of its operations and schemas, so that they can be
+// embedded and served. With bundle, the references to other files are inlined
+// to make the document self-contained.
+//
+// Bundling modifies the document, so this must be called after the rest of the
+// code is generated.
+func GenerateSpec(spec *libopenapi.DocumentModel[v3.Document], outputDir string, bundle bool, output *Output) error {
+	reference, err := specReference(spec)
+	if err != nil {
+		return err
+	}
+	var document []byte
+	if bundle {
+		document, err = bundler.BundleDocument(&spec.Model)
+	} else {
+		document, err = spec.Model.Render()
+	}
+	if err != nil {
+		return fmt.Errorf("cannot render specification: %w", err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(document, &node); err != nil {
+		return fmt.Errorf("cannot decode rendered specification: %w", err)
+	}
+	// The keys keep the order of the specification, unlike when decoding into
+	// a map.
+	documentJSON, err := json.YAMLNodeToJSON(&node, "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode specification as JSON: %w", err)
+	}
+	for name, content := range map[string][]byte{
+		specJSONFile:      append(documentJSON, '\n'),
+		specYAMLFile:      document,
+		specReferenceFile: reference,
+	} {
+		if err := output.WriteFile(filepath.Join(outputDir, name), content); err != nil {
+			return fmt.Errorf("cannot write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// A schema of the components in the reference page.
+type referenceSchema struct {
+	Name        string
+	Description string
+	Source      string
+}
+
+// Renders a self-contained HTML page that lists the operations, with their
+// parameters and responses, and the schemas of the components.
+func specReference(spec *libopenapi.DocumentModel[v3.Document]) ([]byte, error) {
+	var schemas []referenceSchema
+	if spec.Model.Components != nil && spec.Model.Components.Schemas != nil {
+		for pair := spec.Model.Components.Schemas.First(); pair != nil; pair = pair.Next() {
+			schema := pair.Value().Schema()
+			if schema == nil {
+				continue
+			}
+			source, err := schema.Render()
+			if err != nil {
+				return nil, fmt.Errorf("cannot render schema %s: %w", pair.Key(), err)
+			}
+			schemas = append(schemas, referenceSchema{
+				Name:        pair.Key(),
+				Description: schema.Description,
+				Source:      string(source),
+			})
+		}
+	}
+	var title, version, description string
+	if info := spec.Model.Info; info != nil {
+		title, version, description = info.Title, info.Version, info.Description
+	}
+	operations, diagnostics := ExtractOperations(spec, ModelOptions{})
+	if err := diagnostics.Err(); err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	err := referenceTemplate.Execute(&buffer, map[string]any{
+		"Title":       title,
+		"Version":     version,
+		"Description": description,
+		"Operations":  operations,
+		"Schemas":     schemas,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot render reference page: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+var referenceTemplate = template.Must(template.New("reference").Funcs(template.FuncMap{
+	"upper": strings.ToUpper,
+	"join":  strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; max-width: 60rem; margin: 0 auto; padding: 1rem; color: #222; }
+code, pre { font-family: ui-monospace, monospace; }
+pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+section { border-top: 1px solid #ddd; padding: 0.5rem 0; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: 0.25rem 0.75rem 0.25rem 0; vertical-align: top; }
+.method { font-weight: bold; text-transform: uppercase; }
+.deprecated { color: #a00; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}{{if .Version}} <small>{{.Version}}</small>{{end}}</h1>
+{{with .Description}}<p>{{.}}</p>
+{{end}}
+<h2>Operations</h2>
+<ul>
+{{- range .Operations}}
+<li><a href="#operation-{{.Name}}"><span class="method">{{upper .Method}}</span> <code>{{.PathTemplate}}</code></a>{{with .Summary}} {{.}}{{end}}</li>
+{{- end}}
+</ul>
+{{range .Operations}}
+<section id="operation-{{.Name}}">
+<h3><span class="method">{{upper .Method}}</span> <code>{{.PathTemplate}}</code></h3>
+<p><code>{{.Name}}</code>{{if .Deprecated}} <span class="deprecated">deprecated{{with .Sunset}}, removed on {{.}}{{end}}</span>{{end}}</p>
+{{- with .Summary}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- if or .Tags .Security}}
+<p>{{with .Tags}}Tags: {{join . ", "}}.{{end}}{{if and .Tags .Security}} {{end}}{{with .Security}}Security: {{.}}.{{end}}</p>
+{{- end}}
+{{- if .Parameters}}
+<h4>Parameters</h4>
+<table>
+<tr><th>Name</th><th>In</th><th>Required</th><th>Description</th></tr>
+{{- range .Parameters}}
+<tr><td><code>{{.ParamName}}</code></td><td>{{.In}}</td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .RequestBody}}
+<h4>Request body</h4>
+<p><code>{{.RequestBody}}</code>{{with .RequestBodyDescription}} {{.}}{{end}}</p>
+{{- end}}
+{{- if .Responses}}
+<h4>Responses</h4>
+<table>
+<tr><th>Status</th><th>Body</th></tr>
+{{- range .Responses}}
+<tr><td>{{.Code}}</td><td><code>{{.Type}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+</section>
+{{end}}
+{{- if .Schemas}}
+<h2>Schemas</h2>
+{{range .Schemas}}
+<section id="schema-{{.Name}}">
+<h3><code>{{.Name}}</code></h3>
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+<pre>{{.Source}}</pre>
+</section>
+{{end}}
+{{- end}}
+</body>
+</html>
+`))
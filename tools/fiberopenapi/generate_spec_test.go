@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateSpec(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Pets <API>", "version": "1.0.0"},
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"operationId": "find-pet",
+					"summary": "Find a pet",
+					"deprecated": true,
+					"parameters": [{
+						"name": "petId",
+						"in": "path",
+						"description": "ID of the <pet>",
+						"schema": {"type": "integer"}
+					}],
+					"responses": {
+						"200": {
+							"description": "The pet",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pet": {"type": "object", "description": "A pet", "properties": {"name": {"type": "string"}}}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, GenerateSpec(spec, dir, false, nil))
+
+	// The JSON and YAML documents are the same specification.
+	data, err := os.ReadFile(filepath.Join(dir, specJSONFile))
+	require.NoError(t, err)
+	var fromJSON map[string]any
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	data, err = os.ReadFile(filepath.Join(dir, specYAMLFile))
+	require.NoError(t, err)
+	var fromYAML map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &fromYAML))
+	assert.Equal(t, "find-pet", fromJSON["paths"].(map[string]any)["/pets/{petId}"].(map[string]any)["get"].(map[string]any)["operationId"])
+	assert.Equal(t, "find-pet", fromYAML["paths"].(map[string]any)["/pets/{petId}"].(map[string]any)["get"].(map[string]any)["operationId"])
+
+	// The reference page lists the operations and schemas, escaped.
+	data, err = os.ReadFile(filepath.Join(dir, specReferenceFile))
+	require.NoError(t, err)
+	page := string(data)
+	for _, expected := range []string{
+		"<title>Pets &lt;API&gt;</title>",
+		`<section id="operation-FindPet">`,
+		`<span class="method">GET</span> <code>/pets/{petId}</code>`,
+		`<span class="deprecated">deprecated</span>`,
+		"<td><code>petId</code></td><td>path</td><td>yes</td><td>ID of the &lt;pet&gt;</td>",
+		"<tr><td>200</td><td><code>Pet</code></td></tr>",
+		`<section id="schema-Pet">`,
+		"<p>A pet</p>",
+	} {
+		assert.Contains(t, page, expected)
+	}
+	assert.NotContains(t, page, "<script")
+	assert.NotContains(t, page, "http")
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Generates the server, the client and the models of a specification in a
+// package of the module, and runs go test on it with the test files, that can
+// use the generated code.
+func testGeneratedCode(t *testing.T, spec string, testFiles map[string]string) {
+	t.Helper()
+	// The package must be in the module to build with its dependencies.
+	dir, err := os.MkdirTemp(".", "_generated")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.yaml"), []byte(spec), 0o644))
+	for name, content := range testFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	target := Target{
+		Spec:     filepath.Join(dir, "spec.yaml"),
+		Dir:      dir,
+		Package:  "generated",
+		Generate: []string{FeatureServer, FeatureModels, FeatureClient},
+	}
+	_, err = target.Run(nil)
+	require.NoError(t, err)
+	output, err := exec.Command("go", "test", "./"+dir).CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestParameterNames(t *testing.T) {
+	testGeneratedCode(t, `
+openapi: 3.1.0
+info: {title: Names, version: 1.0.0}
+paths:
+  /things/{type}:
+    post:
+      operationId: create-thing
+      parameters:
+        - {name: type, in: path, required: true, schema: {type: string}}
+        - {name: body, in: query, schema: {type: string}}
+        - {name: json, in: query, schema: {type: string}}
+        - {name: err, in: header, schema: {type: string}}
+        - {name: c, in: query, schema: {type: string}}
+        - {name: string, in: cookie, schema: {type: string}}
+        - {name: validate, in: query, schema: {type: string}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object, properties: {name: {type: string}}}
+      responses:
+        "204": {description: Created}
+`, nil)
+}
+
+func TestParameterLocations(t *testing.T) {
+	testGeneratedCode(t, `
+openapi: 3.1.0
+info: {title: Locations, version: 1.0.0}
+paths:
+  /pets/{id}:
+    get:
+      operationId: get-pet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+        - {name: limit, in: query, schema: {type: integer, maximum: 10}}
+        - {name: kind, in: query, deprecated: true, schema: {type: string}}
+        - {name: X-Trace, in: header, schema: {type: string}}
+        - {name: session, in: cookie, required: true, schema: {type: string}}
+      responses:
+        "204": {description: Found}
+`, map[string]string{"parameters_test.go": `package generated
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type handlers struct{ got string }
+
+func (h *handlers) GetPet(c *fiber.Ctx, id GetPetId, limit GetPetLimit, kind GetPetKind, xTrace GetPetXTrace, session GetPetSession) error {
+	h.got = fmt.Sprintf("%v %v %v %v %v", id, limit, kind, xTrace, session)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func TestParameters(t *testing.T) {
+	h := &handlers{}
+	var deprecated []string
+	app := fiber.New()
+	AddHandlers(app, h, WithHandlersDeprecatedParameterHook(func(c *fiber.Ctx, operation, parameter string) {
+		deprecated = append(deprecated, parameter)
+	}))
+	tests := map[string]struct {
+		target, session string
+		status          int
+		got             string
+	}{
+		"all":              {target: "/pets/1?limit=5&kind=cat", session: "abc", status: 204, got: "1 5 cat 42 abc"},
+		"quoted string":    {target: "/pets/1?kind=%22cat%22", session: "abc", status: 204, got: "1 0 cat 42 abc"},
+		"number as string": {target: "/pets/1?kind=7", session: "123", status: 204, got: "1 0 7 42 123"},
+		"optional":         {target: "/pets/1", session: "abc", status: 204, got: "1 0  42 abc"},
+		"invalid":          {target: "/pets/1?limit=many", session: "abc", status: 500},
+		"too large":        {target: "/pets/1?limit=11", session: "abc", status: 500},
+		"required":         {target: "/pets/1", status: 500},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h.got = ""
+			req := httptest.NewRequest("GET", test.target, nil)
+			req.Header.Set("X-Trace", "42")
+			if test.session != "" {
+				req.Header.Set("Cookie", "session="+test.session)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.status || h.got != test.got {
+				t.Errorf("got %d %q, want %d %q", resp.StatusCode, h.got, test.status, test.got)
+			}
+		})
+	}
+	if len(deprecated) == 0 || deprecated[0] != "kind" {
+		t.Errorf("got deprecated parameters %v, want kind", deprecated)
+	}
+}
+`})
+}
+
+func TestReservedModelNames(t *testing.T) {
+	testGeneratedCode(t, `
+openapi: 3.1.0
+info: {title: Reserved, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: get-pet
+      responses:
+        "200":
+          description: The pet.
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        price: {$ref: "#/components/schemas/Decimal"}
+        option: {$ref: "#/components/schemas/PetOption"}
+    Decimal: {type: string}
+    PetOption: {type: string}
+    RawValue: {type: object}
+    Handlers: {type: string}
+    Client: {type: string}
+    GetPetResponse: {type: string}
+`, nil)
+}
+
+func TestDecimalBounds(t *testing.T) {
+	testGeneratedCode(t, `
+openapi: 3.1.0
+info: {title: Decimals, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        price: {type: number, format: decimal, minimum: 0, multipleOf: 0.01}
+        discount: {type: string, format: decimal, exclusiveMinimum: 0, maximum: 1}
+`, map[string]string{"decimals_test.go": `package generated
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDecimals(t *testing.T) {
+	tests := map[string]error{
+		"{\"price\": 12.50, \"discount\": \"0.1\"}": nil,
+		"{\"price\": 0, \"discount\": \"1\"}":       nil,
+		"{\"price\": -5}":                          ErrMinimum,
+		"{\"price\": -0.01}":                       ErrMinimum,
+		"{\"price\": 0.015}":                       ErrMultipleOf,
+		"{\"discount\": \"0\"}":                    ErrExclusiveMinimum,
+		"{\"discount\": 1.0000000000000001}":       ErrMaximum,
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			var order Order
+			if err := json.Unmarshal([]byte(data), &order); err != nil {
+				t.Fatal(err)
+			}
+			if err := order.Validate(); !errors.Is(err, want) {
+				t.Errorf("got %v, want %v", err, want)
+			}
+		})
+	}
+}
+`})
+}
+
+// The configuration of the README that shares the models between services,
+// generated in directories that do not exist yet.
+func TestSharedPackages(t *testing.T) {
+	dir, err := os.MkdirTemp(".", "_generated")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	importPath := "github.com/esdandreu/fiberopenapi/tools/fiberopenapi/" + filepath.Base(dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(`
+openapi: 3.1.0
+info: {title: Shop, version: 1.0.0}
+paths:
+  /orders/{id}:
+    get:
+      operationId: get-order
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: The order.
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Order"}
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        total: {type: number, minimum: 0}
+`), 0o644))
+	config := strings.ReplaceAll(`
+targets:
+  - spec: api.yaml
+    dir: models
+    generate: [models, runtime]
+    runtime-package: {import: example.com/shop/runtime, dir: runtime}
+  - spec: api.yaml
+    dir: services/orders
+    models-package: {import: example.com/shop/models}
+    runtime-package: {import: example.com/shop/runtime}
+`, "example.com/shop", importPath)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fiberopenapi.yaml"), []byte(config), 0o644))
+
+	loaded, err := LoadConfig(filepath.Join(dir, "fiberopenapi.yaml"))
+	require.NoError(t, err)
+	for _, target := range loaded.Targets {
+		_, err := target.Run(nil)
+		require.NoError(t, err)
+	}
+	output, err := exec.Command("go", "vet",
+		"./"+dir+"/models", "./"+dir+"/runtime", "./"+dir+"/services/orders",
+	).CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestWriteOnlyResponses(t *testing.T) {
+	testGeneratedCode(t, `
+openapi: 3.1.0
+info: {title: Accounts, version: 1.0.0}
+paths:
+  /accounts/{name}:
+    get:
+      operationId: get-account
+      parameters:
+        - {name: name, in: path, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: The account.
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name: {type: string}
+                  password: {type: string, writeOnly: true}
+`, map[string]string{"accounts_test.go": `package generated
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type handlers struct{}
+
+func (handlers) GetAccount(c *fiber.Ctx, name GetAccountName) error {
+	if name == "invalid" {
+		return c.Status(fiber.StatusOK).SendString("not an account")
+	}
+	return c.Status(fiber.StatusOK).SendString(` + "`" + `{"name": "Alex", "password": "secret", "extra": 1}` + "`" + `)
+}
+
+func TestAccounts(t *testing.T) {
+	app := fiber.New()
+	AddHandlers(app, handlers{})
+	tests := map[string]string{
+		"/accounts/alex":    ` + "`" + `{"extra":1,"name":"Alex"}` + "`" + `,
+		"/accounts/invalid": "not an account",
+	}
+	for target, want := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != fiber.StatusOK || string(body) != want {
+			t.Errorf("%s: got %d %s, want 200 %s", target, resp.StatusCode, body, want)
+		}
+	}
+}
+`})
+}
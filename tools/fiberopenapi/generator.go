@@ -4,13 +4,29 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
 )
 
-// Contains the generated code. One can append content to it using Printf. Once
-// done, one can use WriteFile to write formatted content to a file.
+// Contains the generated code of a file. One can append content to it using
+// Printf, and register the imports of packages outside of the standard library
+// with Import. Once done, one can use WriteFile to write the formatted file,
+// with its package clause and the imports that its content uses, to an Output.
 type Generator struct {
-	content bytes.Buffer
+	// Name of the package of the file.
+	Package string
+	// Qualifies the identifiers declared in the other generated packages.
+	Qualifier *Qualifier
+	content   bytes.Buffer
+	// Names of the registered imports, empty for the default one, by path.
+	imports map[string]string
+	err     error
 }
 
 func (g *Generator) Printf(format string, args ...any) {
@@ -21,13 +37,144 @@ func (g *Generator) Println(args ...any) {
 	fmt.Fprintln(&g.content, args...)
 }
 
-func (g *Generator) WriteFile(path string) error {
-	src, err := format.Source(g.content.Bytes())
+// Appends the content of a template executed with data. The imports that the
+// template registers with the import function, either paths like log/slog or
+// specs like `money "example.com/money/v2"`, are registered in the file.
+func (g *Generator) Execute(templates *template.Template, name string, data any) error {
+	templates, err := templates.Clone()
 	if err != nil {
-		return fmt.Errorf("failed to format generated code: %w", err)
+		return fmt.Errorf("cannot execute template: %w", err)
 	}
-	err = os.WriteFile(path, src, os.ModePerm)
+	templates.Funcs(template.FuncMap{
+		"import": func(specs ...string) string {
+			for _, spec := range specs {
+				if !strings.Contains(spec, `"`) {
+					spec = strconv.Quote(spec)
+				}
+				g.Import(spec)
+			}
+			return ""
+		},
+	})
+	if err := templates.ExecuteTemplate(&g.content, name, data); err != nil {
+		return fmt.Errorf("cannot execute template: %w", err)
+	}
+	return nil
+}
+
+// Registers an import spec, like `"github.com/gofiber/fiber/v2"` or
+// `money "example.com/money/v2"`. It is written if the content uses it, or if
+// it is a blank import like `_ "embed"`. The packages of the standard library
+// that the generated code uses do not need to be registered.
+func (g *Generator) Import(specs ...string) {
+	for _, spec := range specs {
+		name, importPath, err := parseImportSpec(spec)
+		if err != nil {
+			g.err = err
+			continue
+		}
+		if g.imports == nil {
+			g.imports = map[string]string{}
+		}
+		g.imports[importPath] = name
+	}
+}
+
+// The formatted source of the file.
+func (g *Generator) Source() ([]byte, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	imports, err := g.usedImports()
+	if err != nil {
+		return nil, err
+	}
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n// Code generated by \"fiberopenapi %s\"; DO NOT EDIT.\n",
+		g.Package, strings.Join(generatorArgs(os.Args[1:]), " "),
+	)
+	// The packages of the standard library are grouped before the others.
+	var groups []string
+	for _, standard := range []bool{true, false} {
+		var group string
+		for _, importPath := range slices.Sorted(maps.Keys(imports)) {
+			if isStandardImport(importPath) == standard {
+				group += "\t" + importSpec(imports[importPath], importPath) + "\n"
+			}
+		}
+		if group != "" {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) > 0 {
+		fmt.Fprintf(&src, "\nimport (\n%s)\n", strings.Join(groups, "\n"))
+	}
+	src.Write(g.content.Bytes())
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src.Bytes(), parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	g.Qualifier.rename(file)
+	var formatted bytes.Buffer
+	if err := format.Node(&formatted, fset, file); err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted.Bytes(), nil
+}
+
+// The imports that the content uses, with their names by path. They are the
+// registered imports, the packages of the standard library and the other
+// generated packages whose names the content uses without declaring them.
+func (g *Generator) usedImports() (map[string]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package "+g.Package+"\n"+g.content.String(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated code: %w", err)
+	}
+	used := map[string]bool{}
+	for _, ident := range file.Unresolved {
+		used[ident.Name] = true
+	}
+	imports := map[string]string{}
+	// Paths of the imports by name, to find the ones with the same name.
+	names := map[string]string{}
+	add := func(name, importPath string) error {
+		if other, ok := names[importName(name, importPath)]; ok && other != importPath {
+			return fmt.Errorf("imports %q and %q have the same name %s, set the name of one of them", other, importPath, importName(name, importPath))
+		}
+		names[importName(name, importPath)] = importPath
+		imports[importPath] = name
+		return nil
+	}
+	for _, importPath := range slices.Sorted(maps.Keys(g.imports)) {
+		name := g.imports[importPath]
+		if name == "_" || used[importName(name, importPath)] {
+			if err := add(name, importPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, ident := range slices.Sorted(maps.Keys(used)) {
+		if pkg, ok := g.Qualifier.packageOf(ident); ok {
+			if err := add(pkg.PackageName(), pkg.Import); err != nil {
+				return nil, err
+			}
+		} else if importPath, ok := standardPackages[ident]; ok && names[ident] == "" {
+			if err := add("", importPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return imports, nil
+}
+
+func (g *Generator) WriteFile(output *Output, path string) error {
+	src, err := g.Source()
 	if err != nil {
+		return err
+	}
+	if err := output.WriteFile(path, src); err != nil {
 		return fmt.Errorf("failed to write generated code: %w", err)
 	}
 	return nil
@@ -37,6 +184,28 @@ func (g *Generator) IsEmpty() bool {
 	return g.content.Len() == 0
 }
 
+// Appends the content of another generator, and registers its imports.
 func (g *Generator) MergeIn(other *Generator) {
 	g.content.Write(other.content.Bytes())
+	for importPath, name := range other.imports {
+		g.Import(importSpec(name, importPath))
+	}
+}
+
+// The arguments of the command that generates the code, as written in the
+// generated files, without the -check flag, with which the files are the same
+// as without it.
+func generatorArgs(args []string) []string {
+	return slices.DeleteFunc(slices.Clone(args), func(arg string) bool {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		return strings.HasPrefix(arg, "-") && name == "check"
+	})
+}
+
+// The code of a base file after its package clause and its imports, which the
+// generated files import themselves.
+func baseCode(src string) string {
+	start := strings.Index(src, "import (")
+	end := strings.Index(src[start:], "\n)\n")
+	return src[start+end+len("\n)\n"):]
 }
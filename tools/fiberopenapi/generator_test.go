@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorImports(t *testing.T) {
+	tests := map[string]struct {
+		imports  []string
+		content  string
+		expected string
+		err      string
+	}{
+		"unused imports": {
+			imports: []string{`"github.com/gofiber/fiber/v2"`, `money "example.com/money/v2"`},
+			content: `
+func add(app *fiber.App) {}
+`,
+			expected: `
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+func add(app *fiber.App) {}
+`,
+		},
+		"standard library": {
+			content: `
+func decode(data []byte, v any) error {
+	if len(data) == 0 {
+		return errors.New("empty")
+	}
+	return json.Unmarshal(data, v)
+}
+`,
+			expected: `
+import (
+	"encoding/json"
+	"errors"
+)
+
+func decode(data []byte, v any) error {
+	if len(data) == 0 {
+		return errors.New("empty")
+	}
+	return json.Unmarshal(data, v)
+}
+`,
+		},
+		"grouped imports": {
+			imports: []string{`_ "embed"`, `money "example.com/money/v2"`, `"github.com/gofiber/fiber/v2"`},
+			content: `
+var price money.Amount
+
+func now(c *fiber.Ctx) time.Time { return time.Now() }
+`,
+			expected: `
+import (
+	_ "embed"
+	"time"
+
+	"example.com/money/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+var price money.Amount
+
+func now(c *fiber.Ctx) time.Time { return time.Now() }
+`,
+		},
+		"conflicting names": {
+			imports: []string{`"example.com/a/money"`, `"example.com/b/money"`},
+			content: `
+var price money.Amount
+`,
+			err: `imports "example.com/a/money" and "example.com/b/money" have the same name money`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{Package: "example"}
+			g.Import(test.imports...)
+			g.Printf("%s", test.content)
+			src, err := g.Source()
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			_, content, _ := strings.Cut(string(src), "DO NOT EDIT.\n")
+			assert.Equal(t, test.expected, content)
+		})
+	}
+}
+
+func TestGeneratorExecute(t *testing.T) {
+	templates, err := template.New("log.tmpl").Funcs(templateFuncs).Parse(`{{import "log"}}
+func logged() { log.Print({{quote .}}) }
+`)
+	require.NoError(t, err)
+	g := &Generator{Package: "example"}
+	require.NoError(t, g.Execute(templates, "log.tmpl", "message"))
+	src, err := g.Source()
+	require.NoError(t, err)
+	_, content, _ := strings.Cut(string(src), "DO NOT EDIT.\n")
+	assert.Equal(t, `
+import (
+	"log"
+)
+
+func logged() { log.Print("message") }
+`, content)
+}
+
+func TestGeneratorArgs(t *testing.T) {
+	assert.Equal(t,
+		[]string{"-spec", "./spec.json", "-client"},
+		generatorArgs([]string{"-spec", "./spec.json", "-check", "-client", "--check=true"}),
+	)
+}
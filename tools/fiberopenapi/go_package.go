@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A package of generated code that the code generated in other packages
+// imports, like the models or the runtime helpers.
+type GoPackage struct {
+	// Import path of the package, like example.com/pets/models.
+	Import string `yaml:"import"`
+	// Directory where the package is generated. Without it, the package is
+	// imported but not generated, as another target generates it.
+	Dir string `yaml:"dir"`
+	// Name of the package; defaults to the last element of its import path.
+	Name string `yaml:"name"`
+}
+
+func (p GoPackage) PackageName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return path.Base(p.Import)
+}
+
+// Qualifies the identifiers that generated code uses from other generated
+// packages with the name of their package, like Pet becoming models.Pet. The
+// generator imports these packages.
+type Qualifier struct {
+	// Qualified identifiers and their packages, by identifier.
+	identifiers map[string]string
+	packages    map[string]GoPackage
+}
+
+// Declares the names of a package. With export, the identifiers are the
+// unexported names of the package before they are exported, like validate for
+// runtime.Validate.
+func (q *Qualifier) Add(pkg GoPackage, names []string, export bool) {
+	if q.identifiers == nil {
+		q.identifiers = map[string]string{}
+		q.packages = map[string]GoPackage{}
+	}
+	for _, name := range names {
+		qualified := name
+		if export {
+			qualified = exported(name)
+		} else if !ast.IsExported(name) {
+			continue
+		}
+		q.identifiers[name] = pkg.PackageName() + "." + qualified
+		q.packages[name] = pkg
+	}
+}
+
+// Qualifies the identifiers that a file uses without declaring them. The
+// unresolved identifiers are the ones not declared in the file, which does not
+// include struct fields, selectors and composite literal keys.
+func (q *Qualifier) rename(file *ast.File) {
+	for _, ident := range file.Unresolved {
+		if _, ok := q.packageOf(ident.Name); ok {
+			ident.Name = q.identifiers[ident.Name]
+		}
+	}
+}
+
+// The package that declares an identifier, if it is in another one.
+func (q *Qualifier) packageOf(ident string) (GoPackage, bool) {
+	if q == nil {
+		return GoPackage{}, false
+	}
+	pkg, ok := q.packages[ident]
+	return pkg, ok
+}
+
+// The names of the top level declarations of a Go source, without the
+// methods.
+func declaredNames(src []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse generated code: %w", err)
+	}
+	var names []string
+	for name := range file.Scope.Objects {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Exports the top level declarations of a Go source, renaming them and their
+// uses, like validate to Validate, so that other packages can use them.
+func exportDeclarations(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident.Obj != nil && file.Scope.Lookup(ident.Name) == ident.Obj {
+			ident.Name = exported(ident.Name)
+		}
+		return true
+	})
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func exported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualify(t *testing.T) {
+	q := &Qualifier{}
+	q.Add(GoPackage{Import: "example.com/pets/models"}, []string{"Pet", "Tag", "patternTag"}, false)
+	q.Add(GoPackage{Import: "example.com/pets/runtime", Name: "rt"}, []string{"validate", "Null"}, true)
+
+	tests := map[string]struct {
+		content  string
+		expected string
+	}{
+		"undeclared identifiers": {
+			content: `
+func handle(pet Pet) error {
+	return validate(&pet)
+}
+`,
+			expected: `
+import (
+	"example.com/pets/models"
+	rt "example.com/pets/runtime"
+)
+
+func handle(pet models.Pet) error {
+	return rt.Validate(&pet)
+}
+`,
+		},
+		"declared identifiers": {
+			content: `
+type Pet struct {
+	Tag  *Tag
+	Null bool
+}
+
+type Tag string
+
+func newPet() Pet {
+	return Pet{Null: true}
+}
+`,
+			expected: `
+type Pet struct {
+	Tag  *Tag
+	Null bool
+}
+
+type Tag string
+
+func newPet() Pet {
+	return Pet{Null: true}
+}
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &Generator{Package: "server", Qualifier: q}
+			g.Printf("%s", test.content)
+			src, err := g.Source()
+			require.NoError(t, err)
+			_, content, _ := strings.Cut(string(src), "DO NOT EDIT.\n")
+			assert.Equal(t, test.expected, content)
+		})
+	}
+}
+
+func TestExportDeclarations(t *testing.T) {
+	src, err := exportDeclarations([]byte(`package main
+
+type nullable interface {
+	isNull() bool
+}
+
+func isNull(v any) bool {
+	n, ok := v.(nullable)
+	return ok && n.isNull()
+}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, `package main
+
+type Nullable interface {
+	isNull() bool
+}
+
+func IsNull(v any) bool {
+	n, ok := v.(Nullable)
+	return ok && n.isNull()
+}
+`, string(src))
+}
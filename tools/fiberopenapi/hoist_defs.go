@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Moves the schemas that local references point to through $defs, like
+// "#/components/schemas/Order/$defs/line", to the component schemas and points
+// the references to them instead. libopenapi cannot resolve JSON pointers with
+// a $defs segment that is not the first one. The specification is returned as
+// is when there are no such references.
+func HoistDefs(spec []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return nil, fmt.Errorf("cannot parse specification: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return spec, nil
+	}
+	document := root.Content[0]
+	var references []*yaml.Node
+	collectDefsReferences(document, &references)
+	if len(references) == 0 {
+		return spec, nil
+	}
+
+	schemas := mappingValue(mappingValue(document, "components"), "schemas")
+	if schemas == nil {
+		components := mappingValue(document, "components")
+		if components == nil {
+			components = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setMappingValue(document, "components", components)
+		}
+		schemas = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(components, "schemas", schemas)
+	}
+	// The schemas are hoisted once, even if they are referenced many times.
+	hoisted := map[string]string{}
+	for _, reference := range references {
+		pointer := reference.Value
+		if name, ok := hoisted[pointer]; ok {
+			reference.Value = "#/components/schemas/" + EscapeJSONPointer(name)
+			continue
+		}
+		schema := resolvePointer(document, strings.TrimPrefix(pointer, "#"))
+		if schema == nil {
+			// Left for libopenapi to report.
+			continue
+		}
+		name := hoistedName(schemas, pointer)
+		setMappingValue(schemas, name, schema)
+		hoisted[pointer] = name
+		reference.Value = "#/components/schemas/" + EscapeJSONPointer(name)
+	}
+	return yaml.Marshal(&root)
+}
+
+// Collects the values of the local references that have a $defs segment that
+// is not the first one.
+func collectDefsReferences(node *yaml.Node, references *[]*yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "$ref" && value.Kind == yaml.ScalarNode &&
+				strings.HasPrefix(value.Value, "#/") &&
+				!strings.HasPrefix(value.Value, "#/$defs/") &&
+				strings.Contains(value.Value, "/$defs/") {
+				*references = append(*references, value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		collectDefsReferences(child, references)
+	}
+}
+
+// The name of a hoisted schema, the name of its definition unless another
+// component schema already has it. Then the names of its parents are used
+// too, like "Order_line".
+func hoistedName(schemas *yaml.Node, pointer string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "#/"), "/") {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		if !referenceKeywords[segment] {
+			segments = append(segments, segment)
+		}
+	}
+	slices.Reverse(segments)
+	name := segments[0]
+	for _, segment := range segments[1:] {
+		if mappingValue(schemas, name) == nil {
+			return name
+		}
+		name = segment + "_" + name
+	}
+	for i := 2; mappingValue(schemas, name) != nil; i++ {
+		name = segments[0] + strconv.Itoa(i)
+	}
+	return name
+}
+
+// The node that a JSON pointer, like "/components/schemas/Pet", points to.
+func resolvePointer(node *yaml.Node, pointer string) *yaml.Node {
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if node == nil {
+			return nil
+		}
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		switch node.Kind {
+		case yaml.MappingNode:
+			node = mappingValue(node, segment)
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value,
+	)
+}
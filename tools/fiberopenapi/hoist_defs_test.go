@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHoistDefs(t *testing.T) {
+	testCases := map[string]struct {
+		spec       string
+		references []string
+		hoisted    []string
+	}{
+		"no defs references": {
+			spec: `
+components:
+  schemas:
+    Order:
+      properties:
+        total: {$ref: "#/components/schemas/Money"}
+    Money: {type: string}`,
+			references: []string{"#/components/schemas/Money"},
+			hoisted:    []string{"Order", "Money"},
+		},
+		"defs reference": {
+			spec: `
+components:
+  schemas:
+    Order:
+      $defs:
+        line: {type: object}
+      properties:
+        lines: {items: {$ref: "#/components/schemas/Order/$defs/line"}}
+        first: {$ref: "#/components/schemas/Order/$defs/line"}`,
+			references: []string{"#/components/schemas/line", "#/components/schemas/line"},
+			hoisted:    []string{"Order", "line"},
+		},
+		"name taken": {
+			spec: `
+components:
+  schemas:
+    line: {type: string}
+    Order:
+      $defs:
+        line: {type: object}
+      properties:
+        lines: {items: {$ref: "#/components/schemas/Order/$defs/line"}}`,
+			references: []string{"#/components/schemas/Order_line"},
+			hoisted:    []string{"line", "Order", "Order_line"},
+		},
+		"defs of a file": {
+			spec: `
+components:
+  schemas:
+    Order:
+      properties:
+        total: {$ref: "common.yaml#/components/schemas/Money/$defs/amount"}`,
+			references: []string{"common.yaml#/components/schemas/Money/$defs/amount"},
+			hoisted:    []string{"Order"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec, err := HoistDefs([]byte(tc.spec))
+			require.NoError(t, err)
+
+			var root yaml.Node
+			require.NoError(t, yaml.Unmarshal(spec, &root))
+			var references []string
+			var collect func(node *yaml.Node)
+			collect = func(node *yaml.Node) {
+				if value := mappingValue(node, "$ref"); value != nil {
+					references = append(references, value.Value)
+				}
+				for _, child := range node.Content {
+					collect(child)
+				}
+			}
+			collect(&root)
+			assert.Equal(t, tc.references, references)
+
+			schemas := resolvePointer(root.Content[0], "/components/schemas")
+			var hoisted []string
+			for i := 0; i < len(schemas.Content); i += 2 {
+				hoisted = append(hoisted, schemas.Content[i].Value)
+			}
+			assert.Equal(t, tc.hoisted, hoisted)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Import path of Fiber, that the handlers use.
+const fiberImport = "github.com/gofiber/fiber/v2"
+
+// The packages of the standard library that the generated code uses, by name.
+// They are imported when the generated code refers to them.
+var standardPackages = map[string]string{
+	"big":     "math/big",
+	"bytes":   "bytes",
+	"context": "context",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"http":    "net/http",
+	"io":      "io",
+	"json":    "encoding/json",
+	"math":    "math",
+	"regexp":  "regexp",
+	"slices":  "slices",
+	"strconv": "strconv",
+	"strings": "strings",
+	"time":    "time",
+	"unicode": "unicode",
+	"url":     "net/url",
+	"utf8":    "unicode/utf8",
+}
+
+// Splits an import spec, like `"time"` or `money "example.com/money/v2"`, in
+// its name, empty when it has none, and its path.
+func parseImportSpec(spec string) (name, importPath string, err error) {
+	name, quoted, found := strings.Cut(spec, " ")
+	if !found {
+		name, quoted = "", spec
+	}
+	importPath, err = strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid import spec %s: %w", spec, err)
+	}
+	return name, importPath, nil
+}
+
+// The name that a package is imported with by default, which is the last
+// element of its path without a major version, like money for
+// example.com/money/v2 or yaml for gopkg.in/yaml.v3.
+func defaultPackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if len(elements) > 1 && regexp.MustCompile(`^v[0-9]+$`).MatchString(name) {
+		name = elements[len(elements)-2]
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+// Whether the generated code uses a package with the same name as another
+// one, like errors for github.com/pkg/errors.
+func conflictsWithGeneratedCode(name, importPath string) bool {
+	if standard, ok := standardPackages[name]; ok {
+		return standard != importPath
+	}
+	return name == "fiber" && importPath != fiberImport
+}
+
+// A name for a package that does not conflict with the ones of the generated
+// code, from the last elements of its path, like pkgerrors for
+// github.com/pkg/errors.
+func importAlias(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	alias := defaultPackageName(importPath)
+	if len(elements) > 1 {
+		alias = elements[len(elements)-2] + alias
+	}
+	return strings.ToLower(regexp.MustCompile(`[^a-zA-Z0-9]`).ReplaceAllString(alias, ""))
+}
+
+// Renames the package that a Go type refers to, like errors.Kind to
+// pkgerrors.Kind, also inside composite types like []errors.Kind.
+func renameTypePackage(goType, from, to string) string {
+	expr, err := parser.ParseExpr(goType)
+	if err != nil {
+		return goType
+	}
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == from {
+				ident.Name = to
+			}
+		}
+		return true
+	})
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return goType
+	}
+	return buf.String()
+}
+
+// The package that a Go type refers to, like decimal for decimal.Decimal or
+// *decimal.Decimal, or empty when it has none.
+func typePackage(goType string) string {
+	expr, err := parser.ParseExpr(goType)
+	if err != nil {
+		return ""
+	}
+	var name string
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok && name == "" {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				name = ident.Name
+			}
+		}
+		return name == ""
+	})
+	return name
+}
+
+// Whether an import path is of the standard library, whose first element has
+// no dot, unlike a domain.
+func isStandardImport(importPath string) bool {
+	return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+}
+
+// The import spec of a package with a name, omitted when it is the default
+// one.
+func importSpec(name, importPath string) string {
+	if name == "" || name == defaultPackageName(importPath) {
+		return strconv.Quote(importPath)
+	}
+	return name + " " + strconv.Quote(importPath)
+}
+
+// The name of a package in an import spec that might have none.
+func importName(name, importPath string) string {
+	if name != "" {
+		return name
+	}
+	return defaultPackageName(importPath)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPackageName(t *testing.T) {
+	for importPath, expected := range map[string]string{
+		"time":                        "time",
+		"encoding/json":               "json",
+		"github.com/gofiber/fiber/v2": "fiber",
+		"gopkg.in/yaml.v3":            "yaml",
+		"example.com/money":           "money",
+	} {
+		assert.Equal(t, expected, defaultPackageName(importPath), importPath)
+	}
+}
+
+func TestRenameTypePackage(t *testing.T) {
+	tests := map[string]struct {
+		goType   string
+		expected string
+	}{
+		"named type":     {goType: "errors.Kind", expected: "pkgerrors.Kind"},
+		"composite type": {goType: "map[string][]*errors.Kind", expected: "map[string][]*pkgerrors.Kind"},
+		"other package":  {goType: "money.Amount", expected: "money.Amount"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, renameTypePackage(test.goType, "errors", "pkgerrors"))
+		})
+	}
+}
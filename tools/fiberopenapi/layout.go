@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// How the operations are split in files.
+const (
+	SplitByTag       = "tag"
+	SplitByOperation = "operation"
+)
+
+// How the generated code is laid out in packages and files.
+type Layout struct {
+	// Qualifies the identifiers declared in the other generated packages, nil
+	// when all the code is in one package.
+	Qualifier *Qualifier
+	// Whether the runtime helpers of the models are in their own package,
+	// generated by GenerateRuntime, instead of in the models file.
+	SharedRuntime bool
+	// Splits the code of the operations in a file for each tag, or for each
+	// operation, next to the main file. Operations without tags stay in the
+	// main file. Empty keeps a single file.
+	Split string
+}
+
+// The files generated with a layout: the main one, and the ones of the groups
+// of operations when they are split.
+type layoutFiles struct {
+	layout Layout
+	path   string
+	main   *Generator
+	// The code of the groups of operations, by path.
+	groups map[string]*Generator
+	// Paths of the groups in the order of their first operation.
+	order []string
+}
+
+func newLayoutFiles(layout Layout, path string, main *Generator) *layoutFiles {
+	return &layoutFiles{
+		layout: layout,
+		path:   path,
+		main:   main,
+		groups: map[string]*Generator{},
+	}
+}
+
+// The generator of the code of an operation, in the file of its group.
+func (f *layoutFiles) For(operation Operation) *Generator {
+	var group string
+	switch f.layout.Split {
+	case SplitByTag:
+		if len(operation.Tags) > 0 {
+			group = operation.Tags[0]
+		}
+	case SplitByOperation:
+		group = operation.ID
+	}
+	if group == "" {
+		return f.main
+	}
+	path := fmt.Sprintf("%s_%s.go", strings.TrimSuffix(f.path, ".go"), ToSnakeCase(group))
+	if f.groups[path] == nil {
+		f.groups[path] = &Generator{}
+		f.order = append(f.order, path)
+	}
+	return f.groups[path]
+}
+
+// Writes the files. The ones of the groups have the imports of the main one,
+// of which they only write the ones that they use.
+func (f *layoutFiles) Write(output *Output) error {
+	if err := f.main.WriteFile(output, f.path); err != nil {
+		return fmt.Errorf("cannot write generated code: %w", err)
+	}
+	for _, path := range f.order {
+		g := &Generator{Package: f.main.Package, Qualifier: f.main.Qualifier, imports: maps.Clone(f.main.imports)}
+		g.MergeIn(f.groups[path])
+		if err := g.WriteFile(output, path); err != nil {
+			return fmt.Errorf("cannot write generated code: %w", err)
+		}
+	}
+	return nil
+}
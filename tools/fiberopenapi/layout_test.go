@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutFiles(t *testing.T) {
+	operations := []Operation{
+		{ID: "list-pets", Tags: []string{"Pets", "Store"}},
+		{ID: "get-stats"},
+		{ID: "addPet", Tags: []string{"Pets"}},
+	}
+	tests := map[string]struct {
+		split    string
+		expected []string
+	}{
+		"single file": {
+			expected: []string{"handlers.go", "handlers.go", "handlers.go"},
+		},
+		"by tag": {
+			split:    SplitByTag,
+			expected: []string{"handlers_pets.go", "handlers.go", "handlers_pets.go"},
+		},
+		"by operation": {
+			split:    SplitByOperation,
+			expected: []string{"handlers_list_pets.go", "handlers_get_stats.go", "handlers_add_pet.go"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			main := &Generator{}
+			files := newLayoutFiles(Layout{Split: test.split}, "handlers.go", main)
+			paths := map[*Generator]string{main: "handlers.go"}
+			for i, operation := range operations {
+				g := files.For(operation)
+				for _, path := range files.order {
+					if g == files.groups[path] {
+						paths[g] = path
+					}
+				}
+				assert.Equal(t, test.expected[i], paths[g], operation.ID)
+			}
+		})
+	}
+}
@@ -1,33 +1,114 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
 )
 
-// Read and parse an OpenAPI specification file.
-func LoadOpenAPIDocument(specPath string) (*libopenapi.DocumentModel[v3.Document], error) {
+// Read and parse an OpenAPI specification file. References to other files are
+// resolved relative to the location of the specification file. Swagger 2.0
+// specifications are converted to OpenAPI 3, with warnings about what does not
+// convert.
+func LoadOpenAPIDocument(specPath string) (*libopenapi.DocumentModel[v3.Document], Diagnostics, error) {
 	specByteArray, err := os.ReadFile(specPath)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read specification file at %s: %w", specPath, err)
+		return nil, nil, fmt.Errorf("cannot read specification file at %s: %w", specPath, err)
 	}
-	return loadOpenAPIDocument(specByteArray)
+	specByteArray, diagnostics, err := ConvertSwagger(specByteArray)
+	if err != nil {
+		return nil, nil, err
+	}
+	document, err := loadOpenAPIDocument(specByteArray, filepath.Dir(specPath))
+	return document, diagnostics, err
 }
 
-func loadOpenAPIDocument(specByteArray []byte) (*libopenapi.DocumentModel[v3.Document], error) {
-	document, err := libopenapi.NewDocument(specByteArray)
+func loadOpenAPIDocument(specByteArray []byte, basePath string) (*libopenapi.DocumentModel[v3.Document], error) {
+	specByteArray, err := HoistDefs(specByteArray)
+	if err != nil {
+		return nil, err
+	}
+	document, err := libopenapi.NewDocumentWithConfiguration(
+		specByteArray,
+		&datamodel.DocumentConfiguration{
+			BasePath:            basePath,
+			AllowFileReferences: true,
+			// The errors are returned as diagnostics instead of being logged.
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create new document: %w", err)
 	}
-	model, errors := document.BuildV3Model()
-	if len(errors) > 0 {
-		for i := range errors {
-			fmt.Printf("error: %v\n", errors[i])
-		}
-		return nil, fmt.Errorf("cannot create v3 model from document: %d errors reported", len(errors))
+	model, errs := document.BuildV3Model()
+	if len(errs) > 0 {
+		return nil, modelDiagnostics(specByteArray, errs)
 	}
 	return model, nil
 }
+
+// The errors of building the model of a specification as diagnostics, located
+// at the nodes where they were found, like references that cannot be
+// resolved, in the order of the specification. The same problem is often
+// found more than once, and it is only reported once for each location.
+func modelDiagnostics(spec []byte, errs []error) Diagnostics {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil || len(root.Content) == 0 {
+		root.Content = []*yaml.Node{{}}
+	}
+	type located struct {
+		diagnostic Diagnostic
+		node       *yaml.Node
+	}
+	var found []located
+	reported := map[string]bool{}
+	for _, err := range errs {
+		for _, err := range utils.UnwrapErrors(err) {
+			diagnostic := Diagnostic{Severity: SeverityError, Message: err.Error()}
+			node := &yaml.Node{}
+			var resolvingErr *index.ResolvingError
+			var indexingErr *index.IndexingError
+			switch {
+			case errors.As(err, &resolvingErr) && resolvingErr.Node != nil:
+				if resolvingErr.ErrorRef != nil {
+					diagnostic.Message = resolvingErr.ErrorRef.Error()
+				}
+				node = resolvingErr.Node
+			case errors.As(err, &indexingErr) && indexingErr.Node != nil:
+				node = indexingErr.Node
+			}
+			if pointer, ok := pointerAt(root.Content[0], node.Line, node.Column); ok && node.Line > 0 {
+				diagnostic.Pointer = "#" + pointer
+			}
+			key := diagnostic.Pointer
+			if key == "" {
+				key = diagnostic.Message
+			}
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			found = append(found, located{diagnostic, node})
+		}
+	}
+	slices.SortStableFunc(found, func(a, b located) int {
+		return cmp.Or(cmp.Compare(a.node.Line, b.node.Line), cmp.Compare(a.node.Column, b.node.Column))
+	})
+	var diagnostics Diagnostics
+	for _, located := range found {
+		diagnostics = append(diagnostics, located.diagnostic)
+	}
+	return diagnostics
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"fmt"
+	"go/token"
+	"path/filepath"
 
 	"golang.org/x/tools/go/packages"
 )
 
-// Loads the package name of a directory.
+// Loads the package name of a directory. A directory without Go files, that
+// might not exist yet, names the package after itself, like models for
+// internal/models.
 func LoadPackageName(dir string) (string, error) {
 	cfg := &packages.Config{Mode: packages.NeedName}
 	pkgs, err := packages.Load(cfg, dir)
@@ -16,5 +20,16 @@ func LoadPackageName(dir string) (string, error) {
 	if len(pkgs) == 0 {
 		return "", fmt.Errorf("no packages found")
 	}
-	return pkgs[0].Name, nil
+	if pkgs[0].Name != "" {
+		return pkgs[0].Name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("cannot load package info: %w", err)
+	}
+	name := filepath.Base(abs)
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("%s has no Go files and %q is not a package name: set the package name", dir, name)
+	}
+	return name, nil
 }
@@ -1,32 +1,96 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 )
 
 func main() {
-	var packagePath, outputPath, specPath, typeName string
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	var packagePath, outputPath, configPath, typeName string
+	var client, check bool
+	var target Target
+	flag.StringVar(&configPath, "config", "", "path to a configuration file with the targets to generate, instead of the other flags")
 	flag.StringVar(&packagePath, "path", ".", "path to the package to generate the router for; defaults to current directory")
-	flag.StringVar(&outputPath, "output", "handlers.go", "output file name; defaults to handlers.go")
-	flag.StringVar(&specPath, "spec", "", "path to the OpenAPI specification file; must be set")
-	flag.StringVar(&typeName, "type-name", "Handlers", "name of the interface to generate; defaults to Handlers")
+	flag.StringVar(&outputPath, "output", "", "output file name; defaults to handlers.go, or client.go with -client")
+	flag.StringVar(&target.Spec, "spec", "", "path to the OpenAPI 3 or Swagger 2.0 specification file; must be set")
+	flag.StringVar(&typeName, "type-name", "", "name of the interface, or of the client, to generate; defaults to Handlers, or Client with -client")
+	flag.BoolVar(&client, "client", false, "generate a client instead of the handlers")
+	flag.BoolVar(&check, "check", false, "compare the generated files with the ones on disk, without writing them, and fail with a diff of the stale ones")
+	flag.BoolVar(&target.Int64AsString, "int64-as-string", false, "encode int64 integers as JSON strings; both strings and numbers are decoded")
+	flag.BoolVar(&target.EmbedSpec, "embed-spec", false, "embed the specification and a reference page in the package, to serve them with the handlers")
+	flag.BoolVar(&target.BundleSpec, "bundle-spec", false, "inline the references to other files in the embedded specification")
+	flag.BoolVar(&target.Strict, "strict", false, "fail on warnings as well as on errors")
+	flag.StringVar(&target.Templates, "templates", "", "directory with templates that replace the embedded ones of the generated code")
+	flag.StringVar(&target.Interfaces, "interfaces", "", "declare an interface for the handlers of each tag, embedded by the handlers interface; only tag")
+	flag.StringVar(&target.Split, "split", "", "split the operations in a file for each tag, or for each operation; either tag or operation")
 	flag.Parse()
-	if specPath == "" {
+
+	var targets []Target
+	switch {
+	case configPath != "":
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fiberopenapi: %v\n", err)
+			os.Exit(1)
+		}
+		targets = config.Targets
+	case target.Spec != "":
+		// The generated files are written to the current directory, which is
+		// the directory of the package with go generate.
+		packageName, err := LoadPackageName(packagePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fiberopenapi: %v\n", err)
+			os.Exit(1)
+		}
+		target.Package = packageName
+		target.Generate = []string{FeatureServer, FeatureModels}
+		target.HandlersFile, target.TypeName = outputPath, typeName
+		if client {
+			target.Generate = []string{FeatureClient, FeatureModels}
+			target.ClientFile, target.ClientTypeName = outputPath, typeName
+		}
+		target.setDefaults()
+		if err := target.validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "fiberopenapi: %v\n", err)
+			os.Exit(2)
+		}
+		targets = []Target{target}
+	default:
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	// Load OpenAPI document.
-	spec, err := LoadOpenAPIDocument(specPath)
-	if err != nil {
-		panic(err)
+	// Every target is generated, even if others fail.
+	output := &Output{Check: check}
+	failed := false
+	for _, target := range targets {
+		diagnostics, err := target.Run(output)
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostic.Format(target.Spec))
+		}
+		if err == nil {
+			continue
+		}
+		failed = true
+		// The errors that are diagnostics have been printed already.
+		if !errors.As(err, new(Diagnostics)) {
+			fmt.Fprintf(os.Stderr, "fiberopenapi: %s: %v\n", target.Spec, err)
+		}
 	}
-
-	if err := GenerateHandlers(spec, packagePath, outputPath, typeName); err != nil {
-		panic(err)
+	if failed {
+		os.Exit(1)
 	}
-	if err := GenerateModels(spec, packagePath, "models.go"); err != nil {
-		panic(err)
+	if len(output.Diffs) > 0 {
+		for _, diff := range output.Diffs {
+			fmt.Print(diff)
+		}
+		fmt.Fprintf(os.Stderr, "fiberopenapi: %d generated files are stale, generate them again\n", len(output.Diffs))
+		os.Exit(1)
 	}
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/pb33f/libopenapi"
@@ -14,6 +16,11 @@ type ModelType interface {
 	Name() string
 	Docstring() string
 	Definition() string
+	// Methods declared on the type, emitted after its definition.
+	Methods() string
+	// Import specs, like `"encoding/json"`, required by the definition and
+	// the methods.
+	Imports() []string
 }
 
 // Model is a model that has sub-models. Object and array types in the
@@ -29,31 +36,82 @@ type Model interface {
 type baseModel struct {
 	name   string
 	schema *base.Schema
+	// JSON pointer to the schema in the specification.
+	location string
+	// The model that contains this one, if any.
+	parent Model
+	// Whether the name is given by the specification, as it is the name of a
+	// component or of a referenced schema, instead of derived from where it
+	// is used.
+	named bool
+	// Statements that check the keywords validated against the JSON value once
+	// decoded, like const or if.
+	checks string
 }
 
 func (m *baseModel) Name() string {
 	return m.name
 }
 
+func (m *baseModel) base() *baseModel {
+	return m
+}
+
 func (m *baseModel) Schema() *base.Schema {
 	return m.schema
 }
 
 func (m *baseModel) Docstring() string {
-	if m.schema.Deprecated != nil && *m.schema.Deprecated {
-		if m.schema.Description != "" {
-			return fmt.Sprintf("// Deprecated: %s\n", m.schema.Description)
-		} else {
-			return "// Deprecated\n"
+	return docComment("", schemaDoc(m.schema)...)
+}
+
+func (m *baseModel) Methods() string {
+	return unmarshalJSONMethod(m.name, plainDecode(m.name), m.checks)
+}
+
+func (m *baseModel) Imports() []string {
+	return nil
+}
+
+// An UnmarshalJSON method that decodes a value and then runs the checks of the
+// keywords validated against its JSON value. There is no such method when
+// decoding is plain and there are no checks.
+func unmarshalJSONMethod(name, decode, checks string) string {
+	if checks == "" {
+		if decode == plainDecode(name) {
+			return ""
 		}
+		return fmt.Sprintf(`
+func (m *%s) UnmarshalJSON(data []byte) error {%s
+	return nil
+}
+`,
+			name, decode,
+		)
 	}
-	if m.schema.Description != "" {
-		return fmt.Sprintf("// %s\n", m.schema.Description)
-	}
-	// ? schema.ExternalDocs
-	// ? schema.Example
-	// ? schema.Examples
-	return ""
+	return fmt.Sprintf(`
+// UnmarshalJSON rejects values that are not valid against the keywords that
+// are checked against the JSON value, like const or if.
+func (m *%s) UnmarshalJSON(data []byte) error {%s
+	v := RawValue(data)
+	var errs []error%s
+	return errors.Join(errs...)
+}
+`,
+		name, decode, checks,
+	)
+}
+
+// Decodes a value as the type underlying the model, without its UnmarshalJSON
+// method.
+func plainDecode(name string) string {
+	return fmt.Sprintf(`
+	type plain %s
+	if err := json.Unmarshal(data, (*plain)(m)); err != nil {
+		return err
+	}`,
+		name,
+	)
 }
 
 type nullModel struct {
@@ -61,13 +119,55 @@ type nullModel struct {
 }
 
 func (m *nullModel) Definition() string {
-	return "Null"
+	return "= Null"
+}
+
+func (m *nullModel) Methods() string {
+	return ""
 }
 
 func (m *nullModel) Types() []ModelType {
 	return []ModelType{m}
 }
 
+func newNullModel(name string, schema *base.Schema) *nullModel {
+	return &nullModel{baseModel{name: name, schema: schema}}
+}
+
+// A model of a free-form schema, which accepts any JSON value. It is an alias
+// of RawValue so that it keeps its accessors, or a struct that embeds it when
+// it has its own checks.
+type rawModel struct {
+	baseModel
+}
+
+func (m *rawModel) Definition() string {
+	if m.checks != "" {
+		return "struct {\n\tRawValue\n}"
+	}
+	return "= RawValue"
+}
+
+func (m *rawModel) Methods() string {
+	if m.checks == "" {
+		return ""
+	}
+	return unmarshalJSONMethod(m.name, `
+	if err := m.RawValue.UnmarshalJSON(data); err != nil {
+		return err
+	}`,
+		m.checks,
+	) + m.validateMethod()
+}
+
+func (m *rawModel) Types() []ModelType {
+	return []ModelType{m}
+}
+
+func newRawModel(name string, schema *base.Schema) *rawModel {
+	return &rawModel{baseModel{name: name, schema: schema}}
+}
+
 type stringModel struct {
 	baseModel
 }
@@ -77,12 +177,23 @@ func (m *stringModel) Definition() string {
 	return "string"
 }
 
+func (m *stringModel) Methods() string {
+	return m.baseModel.Methods() + m.validateMethod()
+}
+
+func (m *stringModel) Imports() []string {
+	if m.schema.Pattern != "" {
+		return []string{`"regexp"`}
+	}
+	return nil
+}
+
 func (m *stringModel) Types() []ModelType {
 	return []ModelType{m}
 }
 
 func newStringModel(name string, schema *base.Schema) *stringModel {
-	return &stringModel{baseModel{name, schema}}
+	return &stringModel{baseModel{name: name, schema: schema}}
 }
 
 type booleanModel struct {
@@ -93,17 +204,23 @@ func (m *booleanModel) Definition() string {
 	return "bool"
 }
 
+func (m *booleanModel) Methods() string {
+	return m.baseModel.Methods() + m.validateMethod()
+}
+
 func (m *booleanModel) Types() []ModelType {
 	return []ModelType{m}
 }
 
 func newBooleanModel(name string, schema *base.Schema) *booleanModel {
-	return &booleanModel{baseModel{name, schema}}
+	return &booleanModel{baseModel{name: name, schema: schema}}
 }
 
 type numberModel struct {
 	baseModel
 	defaultType string
+	// Whether int64 integers are encoded as JSON strings.
+	int64AsString bool
 }
 
 func (m *numberModel) Definition() string {
@@ -121,30 +238,110 @@ func (m *numberModel) Definition() string {
 	}
 }
 
+func (m *numberModel) Methods() string {
+	if !m.int64AsString || m.Definition() != "int64" {
+		return m.baseModel.Methods() + m.validateMethod()
+	}
+	return fmt.Sprintf(`
+// MarshalJSON encodes the integer as a JSON string, so that JavaScript clients
+// do not round it.
+func (m %s) MarshalJSON() ([]byte, error) {
+	return marshalInt64String(int64(m))
+}
+`,
+		m.name,
+	) + unmarshalJSONMethod(m.name, `
+	if err := unmarshalInt64String(data, (*int64)(m)); err != nil {
+		return err
+	}`,
+		m.checks,
+	) + m.validateMethod()
+}
+
 func (m *numberModel) Types() []ModelType {
 	return []ModelType{m}
 }
 
 func newNumberModel(name string, schema *base.Schema) *numberModel {
-	return &numberModel{baseModel{name, schema}, "float"}
+	return &numberModel{baseModel{name: name, schema: schema}, "float64", false}
 }
 
 func newIntegerModel(name string, schema *base.Schema) *numberModel {
-	return &numberModel{baseModel{name, schema}, "int"}
+	return &numberModel{baseModel{name: name, schema: schema}, "int", false}
+}
+
+// A model of a number with the decimal format, or of a string with a number
+// in it. It is an alias of Decimal, or of DecimalString for strings, that keep
+// the number exact. It is a struct that embeds them when it has its own checks,
+// of the keywords checked against the JSON value or of bounds like minimum.
+type decimalModel struct {
+	baseModel
+}
+
+// Whether the model has its own checks, besides the format.
+func (m *decimalModel) hasChecks() bool {
+	return m.checks != "" || decimalChecks(m.schema) != ""
+}
+
+// Decimal or DecimalString.
+func (m *decimalModel) baseType() string {
+	if slices.Contains(SchemaTypes(m.schema), "string") {
+		return "DecimalString"
+	}
+	return "Decimal"
+}
+
+func (m *decimalModel) Definition() string {
+	if m.hasChecks() {
+		return fmt.Sprintf("struct {\n\t%s\n}", m.baseType())
+	}
+	return "= " + m.baseType()
+}
+
+func (m *decimalModel) Methods() string {
+	if !m.hasChecks() {
+		return ""
+	}
+	if m.checks == "" {
+		// The methods of the embedded type decode and encode it.
+		return m.validateMethod()
+	}
+	return unmarshalJSONMethod(m.name, fmt.Sprintf(`
+	if err := m.%s.UnmarshalJSON(data); err != nil {
+		return err
+	}`,
+		m.baseType(),
+	),
+		m.checks,
+	) + m.validateMethod()
+}
+
+func (m *decimalModel) Types() []ModelType {
+	return []ModelType{m}
+}
+
+func newDecimalModel(name string, schema *base.Schema) *decimalModel {
+	return &decimalModel{baseModel{name: name, schema: schema}}
 }
 
 type objectModel struct {
 	baseModel
 	properties *orderedmap.Map[string, Model]
+	// Properties that are pointers, either to check their presence or to
+	// break recursive types.
+	pointers map[string]bool
+	// Names of the fields set with the x-go-name extension.
+	fieldNames map[string]string
 }
 
 func (m *objectModel) Definition() string {
 	var def string
 	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
-		property := pair.Value()
-		// TODO(GIA) Apply nullable and required
-		def += fmt.Sprintf("\t%s %s `json:\"%s,omitempty\"`\n",
-			property.Name(), property.Name(), pair.Key(),
+		key := pair.Key()
+		// TODO(GIA) Apply nullable
+		def += docComment("\t", schemaDoc(pair.Value().Schema())...)
+		def += fmt.Sprintf("\t%s %s `json:\"%s\"`\n",
+			m.fieldName(key), m.fieldType(key), m.jsonTag(key),
 		)
 	}
 	if def != "" {
@@ -153,6 +350,106 @@ func (m *objectModel) Definition() string {
 	return "struct {}"
 }
 
+func (m *objectModel) Methods() string {
+	methods := m.baseModel.Methods() + m.validateMethod() + m.constructor()
+	if hasRequestChecks(m.schema, nil) {
+		methods += m.validateRequestMethod()
+	}
+	if hasWriteOnlyProperties(m.schema, nil) {
+		methods += m.omitWriteOnlyMethod()
+	}
+	return methods
+}
+
+func (m *objectModel) Imports() []string {
+	var imports []string
+	for property := range m.properties.ValuesFromOldest() {
+		imports = append(imports, goTypeImports(property)...)
+	}
+	return imports
+}
+
+// Rejects readOnly properties and requires the required writeOnly ones, both
+// the ones of this object and the ones of nested objects.
+func (m *objectModel) validateRequestMethod() string {
+	var body string
+	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
+		property := pair.Value()
+		switch {
+		case isReadOnly(property.Schema()):
+			body += fmt.Sprintf(`
+	if m.%s != nil {
+		errs = append(errs, NewReadOnlyError(%q))
+	}`,
+				m.fieldName(pair.Key()), pair.Key(),
+			)
+		case isWriteOnly(property.Schema()) && slices.Contains(m.schema.Required, pair.Key()):
+			body += fmt.Sprintf(`
+	if m.%s == nil {
+		errs = append(errs, NewRequiredError(%q))
+	}`,
+				m.fieldName(pair.Key()), pair.Key(),
+			)
+		case hasRequestChecks(property.Schema(), nil) && m.pointers[pair.Key()]:
+			body += fmt.Sprintf(`
+	if m.%s != nil {
+		if err := m.%s.ValidateRequest(); err != nil {
+			errs = append(errs, err)
+		}
+	}`,
+				m.fieldName(pair.Key()), m.fieldName(pair.Key()),
+			)
+		case hasRequestChecks(property.Schema(), nil):
+			body += fmt.Sprintf(`
+	if err := m.%s.ValidateRequest(); err != nil {
+		errs = append(errs, err)
+	}`,
+				m.fieldName(pair.Key()),
+			)
+		}
+	}
+	return fmt.Sprintf(`
+// ValidateRequest rejects readOnly properties, which clients must not send in
+// request bodies, and requires the required writeOnly ones.
+func (m *%s) ValidateRequest() error {
+	var errs []error%s
+	return errors.Join(errs...)
+}
+`,
+		m.name, body,
+	)
+}
+
+// Omits writeOnly properties, both the ones of this object and the ones of
+// nested objects.
+func (m *objectModel) omitWriteOnlyMethod() string {
+	var body string
+	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
+		property := pair.Value()
+		switch {
+		case isWriteOnly(property.Schema()):
+			body += fmt.Sprintf("\n\tm.%s = nil", m.fieldName(pair.Key()))
+		case hasWriteOnlyProperties(property.Schema(), nil) && m.pointers[pair.Key()]:
+			body += fmt.Sprintf(`
+	if m.%s != nil {
+		m.%s.OmitWriteOnly()
+	}`,
+				m.fieldName(pair.Key()), m.fieldName(pair.Key()),
+			)
+		case hasWriteOnlyProperties(property.Schema(), nil):
+			body += fmt.Sprintf("\n\tm.%s.OmitWriteOnly()", m.fieldName(pair.Key()))
+		}
+	}
+	return fmt.Sprintf(`
+// OmitWriteOnly removes writeOnly properties, which servers must never send in
+// responses.
+func (m *%s) OmitWriteOnly() {%s
+}
+`,
+		m.name, body,
+	)
+}
+
 func (m *objectModel) Types() []ModelType {
 	flattened := []ModelType{m}
 	for property := range m.properties.ValuesFromOldest() {
@@ -161,12 +458,67 @@ func (m *objectModel) Types() []ModelType {
 	return flattened
 }
 
-func newObjectModel(name string, schema *base.Schema) *objectModel {
+// The Go name of a property.
+func (m *objectModel) fieldName(key string) string {
+	if name, ok := m.fieldNames[key]; ok {
+		return name
+	}
+	return ToPascalCase(key)
+}
+
+// The JSON struct tag of a property. Optional properties are omitted when
+// empty unless the x-omitempty extension says otherwise. Properties that are
+// readOnly or writeOnly are always omitted when nil, even if they are
+// required, as they are only sent in one direction.
+func (m *objectModel) jsonTag(key string) string {
+	property := m.properties.GetOrZero(key)
+	extensions := property.Schema().Extensions
+	if HasExtension(extensions, ExtensionGoJSONIgnore) {
+		return "-"
+	}
+	omitEmpty := !slices.Contains(m.schema.Required, key)
+	GetExtension(extensions, ExtensionOmitEmpty, &omitEmpty)
+	if omitEmpty || isReadOnly(property.Schema()) || isWriteOnly(property.Schema()) {
+		return key + ",omitempty"
+	}
+	return key
+}
+
+// The Go type of a property.
+func (m *objectModel) fieldType(key string) string {
+	property := m.properties.GetOrZero(key)
+	if m.pointers[key] {
+		return "*" + property.Name()
+	}
+	return property.Name()
+}
+
+func (b *modelBuilder) newObjectModel(name, location string, schema *base.Schema) *objectModel {
 	properties := orderedmap.New[string, Model]()
+	model := &objectModel{
+		baseModel{name: name, schema: schema}, properties, map[string]bool{}, map[string]string{},
+	}
+	b.register(model)
 	for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
-		properties.Set(pair.Key(), NewModel(pair.Key(), pair.Value()))
+		property := b.NewModel(
+			pair.Key(), location+"/properties/"+EscapeJSONPointer(pair.Key()), pair.Value(),
+		)
+		setParent(property, model)
+		properties.Set(pair.Key(), property)
+		// A struct cannot contain itself, it must point to itself instead.
+		propertySchema := pair.Value().Schema()
+		if isPointerProperty(schema, pair.Key(), propertySchema) ||
+			containsByValue(propertySchema, schema, nil) {
+			model.pointers[pair.Key()] = true
+		}
+		// The x-go-name of a referenced schema is the name of its type.
+		var goName string
+		if !pair.Value().IsReference() &&
+			GetExtension(propertySchema.Extensions, ExtensionGoName, &goName) {
+			model.fieldNames[pair.Key()] = goName
+		}
 	}
-	model := &objectModel{baseModel{name, schema}, properties}
+	b.checkDefaults(location, schema)
 	return model
 }
 
@@ -179,159 +531,1248 @@ func (m *arrayModel) Definition() string {
 	return fmt.Sprintf("[]%s", m.items.Name())
 }
 
+func (m *arrayModel) Methods() string {
+	methods := m.baseModel.Methods() + m.validateMethod()
+	if hasWriteOnlyProperties(m.schema, nil) {
+		methods += fmt.Sprintf(`
+// OmitWriteOnly removes writeOnly properties of the items, which servers must
+// never send in responses.
+func (m %s) OmitWriteOnly() {
+	for i := range m {
+		m[i].OmitWriteOnly()
+	}
+}
+`,
+			m.name,
+		)
+	}
+	if !hasRequestChecks(m.schema, nil) {
+		return methods
+	}
+	return methods + fmt.Sprintf(`
+// ValidateRequest checks the items as request bodies, which must not have
+// readOnly properties and must have the required writeOnly ones.
+func (m %s) ValidateRequest() error {
+	var errs []error
+	for i := range m {
+		if err := m[i].ValidateRequest(); err != nil {
+			errs = append(errs, fmt.Errorf("%%d: %%w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+`,
+		m.name,
+	)
+}
+
+func (m *arrayModel) Imports() []string {
+	return goTypeImports(m.items)
+}
+
 func (m *arrayModel) Types() []ModelType {
 	return append([]ModelType{m}, m.items.Types()...)
 }
 
-func newArrayModel(name string, schema *base.Schema) *arrayModel {
+func (b *modelBuilder) newArrayModel(name, location string, schema *base.Schema) *arrayModel {
 	if schema.Items == nil {
-		panic(fmt.Errorf("array type must have an items property"))
+		failf(location, "array type must have an items property")
 	}
 	if schema.Items.IsB() {
-		panic(fmt.Errorf("array type with boolean items is not supported"))
+		failf(location+"/items", "array type with boolean items is not supported")
+	}
+	model := &arrayModel{baseModel{name: name, schema: schema}, nil}
+	b.register(model)
+	model.items = b.NewModel(name+"Item", location+"/items", schema.Items.A)
+	setParent(model.items, model)
+	return model
+}
+
+// A model of an array schema with prefixItems, whose items have a type each
+// depending on their position. It is a struct with a field for each of them,
+// that are pointers when the items are optional, and a field for the rest of
+// the items, unless items is false.
+type tupleModel struct {
+	baseModel
+	prefixItems []Model
+	// The model of the rest of the items, or nil if there cannot be more.
+	items Model
+	// The number of prefix items that are required, set with minItems.
+	required int
+}
+
+func (m *tupleModel) Definition() string {
+	def := "struct {\n"
+	for i, item := range m.prefixItems {
+		if i < m.required {
+			def += fmt.Sprintf("\tItem%d %s\n", i, item.Name())
+		} else {
+			def += fmt.Sprintf("\tItem%d *%s\n", i, item.Name())
+		}
+	}
+	if m.items != nil {
+		def += fmt.Sprintf("\tItems []%s\n", m.items.Name())
+	}
+	return def + "}"
+}
+
+func (m *tupleModel) Methods() string {
+	var appends, decode string
+	for i := range m.prefixItems {
+		if i < m.required {
+			appends += fmt.Sprintf("\n\titems = append(items, m.Item%d)", i)
+			decode += fmt.Sprintf(`
+	if err := json.Unmarshal(items[%d], &m.Item%d); err != nil {
+		return err
+	}`,
+				i, i,
+			)
+			continue
+		}
+		appends += fmt.Sprintf(`
+	if m.Item%d == nil {
+		return json.Marshal(items)
+	}
+	items = append(items, m.Item%d)`,
+			i, i,
+		)
+		decode += fmt.Sprintf(`
+	if len(items) > %d {
+		m.Item%d = new(%s)
+		if err := json.Unmarshal(items[%d], m.Item%d); err != nil {
+			return err
+		}
+	}`,
+			i, i, m.prefixItems[i].Name(), i, i,
+		)
+	}
+	if m.items != nil {
+		appends += `
+	for _, item := range m.Items {
+		items = append(items, item)
+	}`
+		decode += fmt.Sprintf(`
+	if len(items) > %d {
+		m.Items = make([]%s, len(items)-%d)
+		for i := range m.Items {
+			if err := json.Unmarshal(items[%d+i], &m.Items[i]); err != nil {
+				return err
+			}
+		}
+	}`,
+			len(m.prefixItems), m.items.Name(), len(m.prefixItems), len(m.prefixItems),
+		)
+	} else {
+		decode += fmt.Sprintf(`
+	if len(items) > %d {
+		return NewItemsError(len(items), %d)
+	}`,
+			len(m.prefixItems), len(m.prefixItems),
+		)
+	}
+	minItems := ""
+	if m.required > 0 {
+		minItems = fmt.Sprintf(`
+	if len(items) < %d {
+		return NewMinItemsError(len(items), %d)
+	}`,
+			m.required, m.required,
+		)
+	}
+	methods := fmt.Sprintf(`
+// MarshalJSON encodes the tuple as an array of its items.
+func (m %s) MarshalJSON() ([]byte, error) {
+	var items []any%s
+	return json.Marshal(items)
+}
+`,
+		m.name, appends,
+	)
+	methods += unmarshalJSONMethod(m.name, fmt.Sprintf(`
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}%s%s`,
+		minItems, decode,
+	),
+		m.checks,
+	)
+	methods += m.validateMethod()
+	if hasWriteOnlyProperties(m.schema, nil) {
+		methods += m.omitWriteOnlyMethod()
+	}
+	if m.items != nil && hasRequestChecks(m.items.Schema(), nil) {
+		methods += fmt.Sprintf(`
+// ValidateRequest checks the items as request bodies, which must not have
+// readOnly properties and must have the required writeOnly ones.
+func (m %s) ValidateRequest() error {
+	var errs []error
+	for i := range m.Items {
+		if err := m.Items[i].ValidateRequest(); err != nil {
+			errs = append(errs, fmt.Errorf("%%d: %%w", %d+i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+`,
+			m.name, len(m.prefixItems),
+		)
+	}
+	return methods
+}
+
+// Omits writeOnly properties of the items, both the ones in their position and
+// the rest of them.
+func (m *tupleModel) omitWriteOnlyMethod() string {
+	var body string
+	for i, item := range m.prefixItems {
+		switch {
+		case !hasWriteOnlyProperties(item.Schema(), nil):
+		case i < m.required:
+			body += fmt.Sprintf("\n\tm.Item%d.OmitWriteOnly()", i)
+		default:
+			body += fmt.Sprintf(`
+	if m.Item%d != nil {
+		m.Item%d.OmitWriteOnly()
+	}`,
+				i, i,
+			)
+		}
+	}
+	if m.items != nil && hasWriteOnlyProperties(m.items.Schema(), nil) {
+		body += `
+	for i := range m.Items {
+		m.Items[i].OmitWriteOnly()
+	}`
 	}
-	items := NewModel(name+"Item", schema.Items.A)
-	return &arrayModel{baseModel{name, schema}, items}
+	return fmt.Sprintf(`
+// OmitWriteOnly removes writeOnly properties of the items, which servers must
+// never send in responses.
+func (m *%s) OmitWriteOnly() {%s
+}
+`,
+		m.name, body,
+	)
+}
+
+func (m *tupleModel) Imports() []string {
+	imports := []string{`"encoding/json"`}
+	for _, item := range m.prefixItems {
+		imports = append(imports, goTypeImports(item)...)
+	}
+	if m.items != nil {
+		imports = append(imports, goTypeImports(m.items)...)
+	}
+	return imports
+}
+
+func (m *tupleModel) Types() []ModelType {
+	flattened := []ModelType{m}
+	for _, item := range m.prefixItems {
+		flattened = append(flattened, item.Types()...)
+	}
+	if m.items != nil {
+		flattened = append(flattened, m.items.Types()...)
+	}
+	return flattened
+}
+
+func (b *modelBuilder) newTupleModel(name, location string, schema *base.Schema) *tupleModel {
+	model := &tupleModel{baseModel{name: name, schema: schema}, nil, nil, 0}
+	if schema.MinItems != nil {
+		model.required = min(int(*schema.MinItems), len(schema.PrefixItems))
+	}
+	b.register(model)
+	for i, proxy := range schema.PrefixItems {
+		item := b.NewModel(
+			fmt.Sprintf("%sItem%d", name, i), fmt.Sprintf("%s/prefixItems/%d", location, i), proxy,
+		)
+		setParent(item, model)
+		model.prefixItems = append(model.prefixItems, item)
+	}
+	switch {
+	case schema.Items != nil && schema.Items.IsA():
+		model.items = b.NewModel(name+"Item", location+"/items", schema.Items.A)
+		setParent(model.items, model)
+	case schema.Items == nil || schema.Items.B:
+		// The rest of the items can be anything.
+		model.items = &referenceModel{baseModel{name: "RawValue", schema: &base.Schema{}}, nil, nil}
+	}
+	return model
 }
 
 // A reference model is a model that doesn't have any model type attached.
 type referenceModel struct {
 	baseModel
+	// The model being referenced, once it has been built.
+	target  Model
+	builder *modelBuilder
+}
+
+func (m *referenceModel) Name() string {
+	if target := m.Target(); target != nil {
+		return target.Name()
+	}
+	return m.name
+}
+
+// The model being referenced, which might be built after the reference.
+func (m *referenceModel) Target() Model {
+	if m.target == nil && m.builder != nil {
+		m.target = m.builder.models[schemaKey(m.schema)]
+	}
+	return m.target
 }
 
 func (m *referenceModel) Types() []ModelType {
 	return []ModelType{}
 }
 
-func newReferenceModel(proxy *base.SchemaProxy) *referenceModel {
+// Models a reference to a schema. The first time that a schema that is not
+// one of the document component schemas is referenced, for example a schema
+// in another file or a property of another schema, its model is built and
+// returned instead, so that its types are generated once.
+func (b *modelBuilder) newReferenceModel(location string, proxy *base.SchemaProxy) Model {
+	proxy = b.finalReference(proxy)
+	schema := proxy.Schema()
+	// The referenced schema of the components is identified by its own node,
+	// which the resolved schema of a chained reference does not have.
+	if target := b.componentSchemaAt(proxy); target != nil && !target.IsReference() {
+		schema = target.Schema()
+	}
+	if schema == nil {
+		failf(location, "cannot resolve reference %s: %v",
+			proxy.GetReference(), proxy.GetBuildError(),
+		)
+	}
+	if target, ok := b.models[schemaKey(schema)]; ok {
+		return &referenceModel{baseModel{name: target.Name(), schema: schema}, target, b}
+	}
 	reference := proxy.GetReference()
-	if !strings.HasPrefix(reference, "#/components/schemas/") {
-		panic(fmt.Errorf("reference not supported: %s", reference))
+	if b.isComponentSchema(reference, schema) {
+		// The component schema is modeled with its own name, either
+		// before or after this reference.
+		name := ToPascalCase(strings.TrimPrefix(reference, "#/components/schemas/"))
+		return &referenceModel{baseModel{name: name, schema: schema}, nil, b}
+	}
+	model := b.newModel(ReferenceName(reference), reference, schema)
+	model.(hasBase).base().named = true
+	b.share(model)
+	return model
+}
+
+// Whether the reference points to one of the schemas in the components of the
+// document. The same reference in another file points to that file instead.
+func (b *modelBuilder) isComponentSchema(reference string, schema *base.Schema) bool {
+	if !strings.HasPrefix(reference, "#/components/schemas/") || b.components == nil {
+		return false
+	}
+	name := strings.TrimPrefix(reference, "#/components/schemas/")
+	component := b.components.Schemas.GetOrZero(name)
+	return component != nil && schemaKey(component.Schema()) == schemaKey(schema)
+}
+
+// Follows a reference to a schema of the components that is a reference
+// itself, like #/components/schemas/Order/properties/total to Money, to the
+// last reference of the chain, so that the schema it points to is modeled
+// once. The schema of such a reference is resolved, but it is identified by
+// the node of the reference in the middle of the chain.
+func (b *modelBuilder) finalReference(proxy *base.SchemaProxy) *base.SchemaProxy {
+	seen := map[string]bool{}
+	for proxy.IsReference() && !seen[proxy.GetReference()] {
+		seen[proxy.GetReference()] = true
+		next := b.componentSchemaAt(proxy)
+		if next == nil || !next.IsReference() {
+			return proxy
+		}
+		proxy = next
+	}
+	return proxy
+}
+
+// The schema that a reference into the schemas of the components of the
+// document points to, like #/components/schemas/Order/properties/total,
+// through properties, items and prefixItems, or nil. The same reference in
+// another file points to that file instead.
+func (b *modelBuilder) componentSchemaAt(reference *base.SchemaProxy) *base.SchemaProxy {
+	if !strings.HasPrefix(reference.GetReference(), "#/components/schemas/") || b.components == nil {
+		return nil
 	}
-	name := ToPascalCase(strings.TrimPrefix(reference, "#/components/schemas/"))
-	return &referenceModel{baseModel{name, proxy.Schema()}}
+	segments := strings.Split(strings.TrimPrefix(reference.GetReference(), "#/components/schemas/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	proxy := b.components.Schemas.GetOrZero(segments[0])
+	if proxy == nil || proxy.GoLow() == nil || reference.GoLow() == nil ||
+		proxy.GoLow().GetIndex() != reference.GoLow().GetIndex() {
+		return nil
+	}
+	for i := 1; proxy != nil && i < len(segments); i++ {
+		schema := proxy.Schema()
+		if schema == nil {
+			return nil
+		}
+		switch {
+		case segments[i] == "properties" && i+1 < len(segments) && schema.Properties != nil:
+			i++
+			proxy = schema.Properties.GetOrZero(segments[i])
+		case segments[i] == "items" && schema.Items != nil && schema.Items.IsA():
+			proxy = schema.Items.A
+		case segments[i] == "prefixItems" && i+1 < len(segments):
+			i++
+			index, err := strconv.Atoi(segments[i])
+			if err != nil || index < 0 || index >= len(schema.PrefixItems) {
+				return nil
+			}
+			proxy = schema.PrefixItems[index]
+		default:
+			return nil
+		}
+	}
+	return proxy
+}
+
+// A model of a schema that is an existing Go type, set with the x-go-type
+// extension. There is no type to generate for it.
+type goTypeModel struct {
+	baseModel
+	imports []string
 }
 
-// TODO(GIA) unionModel
+func (m *goTypeModel) Types() []ModelType {
+	return []ModelType{}
+}
 
-func NewModel(name string, schemaProxy *base.SchemaProxy) Model {
+func newGoTypeModel(schema *base.Schema) *goTypeModel {
+	model := &goTypeModel{baseModel{name: goType(schema), schema: schema}, nil}
+	spec := GetGoTypeImport(schema.Extensions)
+	name, importPath, err := parseImportSpec(spec)
+	if spec == "" || err != nil {
+		return model
+	}
+	// The package is imported with the name that the type uses, and with
+	// another one if the generated code uses a package with the same name,
+	// like errors for github.com/pkg/errors.
+	if name == "" {
+		name = typePackage(model.name)
+		spec = importSpec(name, importPath)
+	}
+	if conflictsWithGeneratedCode(importName(name, importPath), importPath) {
+		alias := importAlias(importPath)
+		model.name = renameTypePackage(model.name, importName(name, importPath), alias)
+		spec = importSpec(alias, importPath)
+	}
+	model.imports = []string{spec}
+	return model
+}
+
+// The x-go-type of a schema, if any.
+func goType(schema *base.Schema) string {
+	var name string
+	GetExtension(schema.Extensions, ExtensionGoType, &name)
+	return name
+}
+
+// The imports needed to use a model that is an existing Go type.
+func goTypeImports(model Model) []string {
+	if reference, ok := model.(*referenceModel); ok {
+		model = reference.Target()
+	}
+	if model, ok := model.(*goTypeModel); ok {
+		return model.imports
+	}
+	return nil
+}
+
+// A model of a schema that accepts values of several types, like
+// type: [string, "null"]. It is a struct that holds a value of one of them,
+// with an accessor and a constructor for each type.
+type unionModel struct {
+	baseModel
+	members []unionMember
+}
+
+// One of the types of a union.
+type unionMember struct {
+	// The name of the type in the accessors and constructors, like String.
+	kind string
+	// The model of the values of this type.
+	model Model
+}
+
+func (m *unionModel) Definition() string {
+	return "struct {\n\tvalue any\n}"
+}
+
+func (m *unionModel) Methods() string {
+	var methods string
+	for _, member := range m.members {
+		methods += fmt.Sprintf(`
+func (u *%s) %s() (%s, bool) {
+	v, ok := u.value.(%s)
+	return v, ok
+}
+
+func (u *%s) Is%s() bool {
+	_, ok := u.%s()
+	return ok
+}
+`,
+			m.name, member.kind, member.model.Name(), member.model.Name(),
+			m.name, member.kind, member.kind,
+		)
+		if member.kind == "Null" {
+			methods += fmt.Sprintf(`
+func New%sAsNull() *%s {
+	return &%s{value: Null{}}
+}
+`,
+				m.name, m.name, m.name,
+			)
+			continue
+		}
+		methods += fmt.Sprintf(`
+func New%sAs%s(v %s) *%s {
+	return &%s{value: v}
+}
+`,
+			m.name, member.kind, member.model.Name(), m.name, m.name,
+		)
+	}
+	methods += m.unmarshalJSONMethod()
+	methods += m.validateMethod()
+	methods += fmt.Sprintf(`
+func (u %s) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.value)
+}
+`,
+		m.name,
+	)
+	if hasRequestChecks(m.schema, nil) {
+		methods += m.validateRequestMethod()
+	}
+	if hasWriteOnlyProperties(m.schema, nil) {
+		methods += m.omitWriteOnlyMethod()
+	}
+	return methods
+}
+
+// Null is tried first, as other types might accept it, and integers are tried
+// before numbers, as numbers accept integers too. The rest of the types do not
+// overlap.
+func (m *unionModel) unmarshalJSONMethod() string {
+	members := slices.Clone(m.members)
+	slices.SortStableFunc(members, func(a, b unionMember) int {
+		return unionMemberOrder(a) - unionMemberOrder(b)
+	})
+	var body string
+	for _, member := range members {
+		body += fmt.Sprintf(`
+	var as%s %s
+	if err := json.Unmarshal(data, &as%s); err == nil {
+		u.value = as%s
+		return nil
+	} else {
+		errs = append(errs, err)
+	}`,
+			member.kind, member.model.Name(), member.kind, member.kind,
+		)
+	}
+	return fmt.Sprintf(`
+func (u *%s) UnmarshalJSON(data []byte) error {
+	var errs []error%s
+	return errors.Join(errs...)
+}
+`,
+		m.name, body,
+	)
+}
+
+func unionMemberOrder(member unionMember) int {
+	switch member.kind {
+	case "Null":
+		return 0
+	case "Integer":
+		return 1
+	}
+	return 2
+}
+
+// Rejects readOnly properties of the value, which clients must not send in
+// request bodies.
+func (m *unionModel) validateRequestMethod() string {
+	var cases string
+	for _, member := range m.members {
+		if _, ok := member.model.(*objectModel); ok ||
+			member.kind == "Array" && hasRequestChecks(member.model.Schema(), nil) {
+			cases += fmt.Sprintf("\n\tcase %s:\n\t\treturn v.ValidateRequest()",
+				member.model.Name(),
+			)
+		}
+	}
+	return fmt.Sprintf(`
+// ValidateRequest checks the value as a request body, which must not have
+// readOnly properties and must have the required writeOnly ones.
+func (u *%s) ValidateRequest() error {
+	switch v := u.value.(type) {%s
+	}
+	return nil
+}
+`,
+		m.name, cases,
+	)
+}
+
+// Omits writeOnly properties of the value, which servers must never send in
+// responses. Objects are held by value, so they are updated in the union.
+func (m *unionModel) omitWriteOnlyMethod() string {
+	var cases string
+	for _, member := range m.members {
+		if !hasWriteOnlyProperties(member.model.Schema(), nil) {
+			continue
+		}
+		cases += fmt.Sprintf("\n\tcase %s:\n\t\tv.OmitWriteOnly()", member.model.Name())
+		if _, ok := member.model.(*objectModel); ok {
+			cases += "\n\t\tu.value = v"
+		}
+	}
+	return fmt.Sprintf(`
+// OmitWriteOnly removes writeOnly properties, which servers must never send in
+// responses.
+func (u *%s) OmitWriteOnly() {
+	switch v := u.value.(type) {%s
+	}
+}
+`,
+		m.name, cases,
+	)
+}
+
+func (m *unionModel) Imports() []string {
+	imports := []string{`"encoding/json"`, `"errors"`}
+	if slices.Contains(SchemaTypes(m.schema), "string") && m.schema.Pattern != "" {
+		imports = append(imports, `"regexp"`)
+	}
+	for _, member := range m.members {
+		imports = append(imports, goTypeImports(member.model)...)
+	}
+	return imports
+}
+
+func (m *unionModel) Types() []ModelType {
+	flattened := []ModelType{m}
+	for _, member := range m.members {
+		flattened = append(flattened, member.model.Types()...)
+	}
+	return flattened
+}
+
+// Builds a union of the given types of a schema. Scalar values are held as
+// builtin Go types while objects and arrays are modeled as their own types,
+// named after the union, like PetObject.
+func (b *modelBuilder) newUnionModel(name, location string, schemaTypes []string, schema *base.Schema) *unionModel {
+	model := &unionModel{baseModel{name: name, schema: schema}, nil}
+	b.register(model)
+	for _, schemaType := range schemaTypes {
+		kind := ToPascalCase(schemaType)
+		// The members are modeled from a copy of the schema with a single
+		// type and without the keywords of the other types. It shares the
+		// node of the schema, so it replaces the union in the models and the
+		// union is registered back after each member.
+		memberSchema := *schema
+		memberSchema.Type = []string{schemaType}
+		if schemaType != "object" {
+			memberSchema.Properties = nil
+		}
+		if schemaType != "array" {
+			memberSchema.Items = nil
+			memberSchema.PrefixItems = nil
+		}
+		if schemaType == "integer" && !strings.HasPrefix(schema.Format, "int") {
+			memberSchema.Format = ""
+		}
+		member := b.newModelOfSingleType(name+kind, location, schemaType, &memberSchema)
+		b.register(model)
+		switch typed := member.(type) {
+		case *objectModel, *arrayModel, *tupleModel:
+			member.(hasBase).base().location = location
+			member.(hasBase).base().checks = valueChecks(location, &memberSchema)
+			setParent(member, model)
+		case *nullModel:
+			member = &referenceModel{baseModel{name: "Null", schema: &memberSchema}, nil, nil}
+		case *decimalModel:
+			member = &referenceModel{baseModel{name: typed.baseType(), schema: &memberSchema}, nil, nil}
+		default:
+			// Scalars are held as the builtin types that they are defined as.
+			definition := member.(ModelType).Definition()
+			member = &referenceModel{baseModel{name: definition, schema: &memberSchema}, nil, nil}
+		}
+		model.members = append(model.members, unionMember{kind, member})
+	}
+	return model
+}
+
+func isReadOnly(schema *base.Schema) bool {
+	return schema != nil && schema.ReadOnly != nil && *schema.ReadOnly
+}
+
+func isWriteOnly(schema *base.Schema) bool {
+	return schema != nil && schema.WriteOnly != nil && *schema.WriteOnly
+}
+
+// Whether a property of an object schema is a pointer. Optional properties
+// are pointers so that they can be omitted, unless they are arrays or the
+// x-go-type-skip-optional-pointer extension is set. Unions that might be
+// arrays and tuples are pointers still. Properties that are
+// readOnly or writeOnly are always pointers so that their presence can be
+// checked and they can be omitted.
+func isPointerProperty(schema *base.Schema, key string, property *base.Schema) bool {
+	if isReadOnly(property) || isWriteOnly(property) {
+		return true
+	}
+	if slices.Contains(schema.Required, key) ||
+		HasExtension(property.Extensions, ExtensionGoTypeSkipOptionalPointer) {
+		return false
+	}
+	return !slices.Equal(SchemaTypes(property), []string{"array"}) || len(property.PrefixItems) > 0
+}
+
+// Identifies a schema by its node in the specification, as a different Schema
+// is built for each reference to the same node.
+func schemaKey(schema *base.Schema) any {
+	if low := schema.GoLow(); low != nil && low.RootNode != nil {
+		return low.RootNode
+	}
+	return schema
+}
+
+// Whether the struct modeling the schema from would contain the struct
+// modeling the schema to by value, through properties that are not pointers.
+// Slices do not count as they are references already.
+func containsByValue(from, to *base.Schema, visited map[any]bool) bool {
+	if from == nil || to == nil {
+		return false
+	}
+	if schemaKey(from) == schemaKey(to) {
+		return true
+	}
+	if visited[schemaKey(from)] || from.Properties == nil || goType(from) != "" {
+		return false
+	}
+	if visited == nil {
+		visited = map[any]bool{}
+	}
+	visited[schemaKey(from)] = true
+	for pair := from.Properties.First(); pair != nil; pair = pair.Next() {
+		propertySchema := pair.Value().Schema()
+		if isPointerProperty(from, pair.Key(), propertySchema) {
+			continue
+		}
+		if containsByValue(propertySchema, to, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the schema has readOnly or required writeOnly properties, or
+// contains items or properties that have them. Such models implement
+// ValidateRequest.
+func hasRequestChecks(schema *base.Schema, visited map[any]bool) bool {
+	if schema == nil || visited[schemaKey(schema)] || goType(schema) != "" {
+		return false
+	}
+	if visited == nil {
+		visited = map[any]bool{}
+	}
+	visited[schemaKey(schema)] = true
+	if schema.Items != nil && schema.Items.IsA() {
+		if hasRequestChecks(schema.Items.A.Schema(), visited) {
+			return true
+		}
+	}
+	if schema.Properties == nil {
+		return false
+	}
+	for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+		propertySchema := pair.Value().Schema()
+		if isReadOnly(propertySchema) ||
+			isWriteOnly(propertySchema) && slices.Contains(schema.Required, pair.Key()) {
+			return true
+		}
+		if hasRequestChecks(propertySchema, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the schema has writeOnly properties, or contains items or
+// properties that have them. Such models implement OmitWriteOnly.
+func hasWriteOnlyProperties(schema *base.Schema, visited map[any]bool) bool {
+	if schema == nil || visited[schemaKey(schema)] || goType(schema) != "" {
+		return false
+	}
+	if visited == nil {
+		visited = map[any]bool{}
+	}
+	visited[schemaKey(schema)] = true
+	if schema.Items != nil && schema.Items.IsA() {
+		if hasWriteOnlyProperties(schema.Items.A.Schema(), visited) {
+			return true
+		}
+	}
+	for _, item := range schema.PrefixItems {
+		if hasWriteOnlyProperties(item.Schema(), visited) {
+			return true
+		}
+	}
+	if schema.Properties == nil {
+		return false
+	}
+	for property := range schema.Properties.ValuesFromOldest() {
+		propertySchema := property.Schema()
+		if isWriteOnly(propertySchema) {
+			return true
+		}
+		if hasWriteOnlyProperties(propertySchema, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Builds the models of the schemas of a document. It keeps track of the
+// schemas that have been modeled already so that references to them, and
+// recursive schemas in particular, reuse their models instead of building
+// them again.
+type modelBuilder struct {
+	components *v3.Components
+	operations map[*v3.Operation]operationModels
+	models     map[any]Model
+	// Models that are shared, the ones of the components and the ones of
+	// referenced schemas. Other schemas reuse them instead of being modeled
+	// again, for example the schema of a referenced parameter.
+	shared  map[any]bool
+	options ModelOptions
+	// The problems found in the specification while building the models.
+	diagnostics Diagnostics
+}
+
+func newModelBuilder(components *v3.Components) *modelBuilder {
+	return &modelBuilder{
+		components: components,
+		operations: map[*v3.Operation]operationModels{},
+		models:     map[any]Model{},
+		shared:     map[any]bool{},
+	}
+}
+
+// Gives access to the fields that all models have in common.
+type hasBase interface {
+	base() *baseModel
+}
+
+func setParent(model, parent Model) {
+	model.(hasBase).base().parent = parent
+}
+
+// Registers a model before building its sub-models, so that the sub-models
+// can reference it.
+func (b *modelBuilder) register(model Model) {
+	b.models[schemaKey(model.Schema())] = model
+}
+
+func (b *modelBuilder) share(model Model) {
+	b.shared[schemaKey(model.Schema())] = true
+}
+
+// Builds the model of a schema located at a JSON pointer in the specification.
+// Models of references do not have any type, so the Types of a model always
+// terminate, even for recursive schemas.
+//
+// When the schema cannot be modeled, the diagnostic is collected and a raw
+// model is returned instead, so that the rest of the specification is checked
+// too.
+func (b *modelBuilder) NewModel(name, location string, schemaProxy *base.SchemaProxy) (model Model) {
+	defer func() {
+		if r := recover(); r != nil {
+			diagnostic, ok := r.(Diagnostic)
+			if !ok {
+				panic(r)
+			}
+			b.diagnostics = append(b.diagnostics, diagnostic)
+			schema := schemaProxy.Schema()
+			if schema == nil {
+				schema = &base.Schema{}
+			}
+			model = newRawModel(ToPascalCase(name), schema)
+		}
+	}()
 	if schemaProxy.IsReference() {
-		return newReferenceModel(schemaProxy)
+		return b.newReferenceModel(location, schemaProxy)
 	}
-	modelName := ToPascalCase(name)
 	schema := schemaProxy.Schema()
-	// TODO(GIA) Check if schema.Type is empty.
-	schemaType := schema.Type[0]
+	if target, ok := b.models[schemaKey(schema)]; ok && b.shared[schemaKey(schema)] {
+		return &referenceModel{baseModel{name: target.Name(), schema: schema}, target, b}
+	}
+	return b.newModel(name, location, schema)
+}
+
+func (b *modelBuilder) newModel(name, location string, schema *base.Schema) Model {
+	checkSupportedKeywords(location, schema)
+	checkPattern(location, schema)
+	var model Model
+	var goName string
+	switch {
+	case goType(schema) != "":
+		model = newGoTypeModel(schema)
+	case GetExtension(schema.Extensions, ExtensionGoName, &goName):
+		model = b.newModelOfType(goName, location, schema)
+		model.(hasBase).base().named = true
+	default:
+		model = b.newModelOfType(ToPascalCase(name), location, schema)
+	}
+	model.(hasBase).base().location = location
+	if _, ok := model.(*unionModel); !ok && goType(schema) == "" {
+		model.(hasBase).base().checks = valueChecks(location, schema)
+	}
+	b.register(model)
+	return model
+}
+
+func (b *modelBuilder) newModelOfType(modelName, location string, schema *base.Schema) Model {
+	schemaTypes := SchemaTypes(schema)
+	switch len(schemaTypes) {
+	case 0:
+		return newRawModel(modelName, schema)
+	case 1:
+		return b.newModelOfSingleType(modelName, location, schemaTypes[0], schema)
+	}
+	return b.newUnionModel(modelName, location, schemaTypes, schema)
+}
+
+func (b *modelBuilder) newModelOfSingleType(modelName, location, schemaType string, schema *base.Schema) Model {
 	switch schemaType {
+	case "null":
+		return newNullModel(modelName, schema)
 	case "boolean":
 		return newBooleanModel(modelName, schema)
 	case "object":
-		return newObjectModel(modelName, schema)
+		return b.newObjectModel(modelName, location, schema)
 	case "array":
-		return newArrayModel(modelName, schema)
-	case "number":
-		return newNumberModel(modelName, schema)
+		if len(schema.PrefixItems) > 0 {
+			return b.newTupleModel(modelName, location, schema)
+		}
+		return b.newArrayModel(modelName, location, schema)
+	case "number", "string":
+		if schema.Format == "decimal" {
+			return newDecimalModel(modelName, schema)
+		}
+		if schemaType == "string" {
+			return newStringModel(modelName, schema)
+		}
+		model := newNumberModel(modelName, schema)
+		model.int64AsString = b.options.Int64AsString
+		return model
 	case "integer":
-		return newIntegerModel(modelName, schema)
-	case "string":
-		return newStringModel(modelName, schema)
+		model := newIntegerModel(modelName, schema)
+		model.int64AsString = b.options.Int64AsString
+		return model
 	}
-	panic(fmt.Errorf("unsupported type: %s", schemaType))
+	failf(location+"/type", "unsupported type: %s", schemaType)
+	return nil
+}
+
+// Options that change how the schemas are modeled.
+type ModelOptions struct {
+	// Encode int64 integers as JSON strings, like Google APIs do, as
+	// JavaScript clients round the ones that do not fit in a float64. Both
+	// strings and numbers are accepted when decoding.
+	Int64AsString bool
+	// The names that the other code generated in the package of the models
+	// declares, like the handlers or the runtime, which the models cannot
+	// use.
+	Reserved []string
 }
 
-func ExtractModelTypesFromDocument(spec *libopenapi.DocumentModel[v3.Document]) []ModelType {
+// Extracts the types to generate from the document, with the problems found
+// in it. The types are only complete if none of the diagnostics is an error.
+func ExtractModelTypesFromDocument(spec *libopenapi.DocumentModel[v3.Document], options ModelOptions) ([]ModelType, Diagnostics) {
 	if spec == nil {
-		return nil
+		return nil, nil
 	}
-	// Extract the model tree from the document (each model can have
-	// nested models).
-	var models []Model
-	models = append(models, extractModelsFromComponents(spec.Model.Components)...)
-	models = append(models, extractModelsFromPaths(spec.Model.Paths)...)
+	b := newModelBuilder(spec.Model.Components)
+	b.options = options
+	models := b.extractModelsFromDocument(spec)
 	// Flatten the models into a single slice of model types.
 	var modelTypes []ModelType
 	for _, model := range models {
 		modelTypes = append(modelTypes, model.Types()...)
 	}
-	return modelTypes
+	return modelTypes, b.diagnostics
+}
+
+// Extracts the model tree from the document (each model can have nested
+// models) and gives unique names to the models.
+func (b *modelBuilder) extractModelsFromDocument(spec *libopenapi.DocumentModel[v3.Document]) []Model {
+	var models []Model
+	models = append(models, b.extractModelsFromComponents(spec.Model.Components)...)
+	models = append(models, b.extractModelsFromPaths(spec.Model.Paths)...)
+	diagnostics, err := disambiguate(models, b.options.Reserved)
+	b.diagnostics = append(b.diagnostics, diagnostics...)
+	if err != nil {
+		b.report(SeverityError, "", "%v", err)
+	}
+	return models
+}
+
+// Collects a diagnostic at a JSON pointer in the specification.
+func (b *modelBuilder) report(severity Severity, pointer, format string, args ...any) {
+	b.diagnostics = append(b.diagnostics, Diagnostic{
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+		Pointer:  pointer,
+	})
 }
 
-func extractModelsFromComponents(components *v3.Components) []Model {
+func (b *modelBuilder) extractModelsFromComponents(components *v3.Components) []Model {
 	if components == nil {
 		return nil
 	}
 	var models []Model
 	for pair := components.Schemas.First(); pair != nil; pair = pair.Next() {
-		models = append(models, NewModel(pair.Key(), pair.Value()))
+		models = append(models, b.NewModel(
+			pair.Key(), "#/components/schemas/"+EscapeJSONPointer(pair.Key()), pair.Value(),
+		))
+	}
+	// Parameters, request bodies, responses and headers that are components
+	// are modeled once, with the name of the component.
+	for pair := components.Parameters.First(); pair != nil; pair = pair.Next() {
+		if pair.Value().Schema == nil {
+			continue
+		}
+		models = append(models, b.NewModel(
+			componentName(pair.Key(), "Parameter"),
+			"#/components/parameters/"+EscapeJSONPointer(pair.Key())+"/schema",
+			pair.Value().Schema,
+		))
+	}
+	for pair := components.RequestBodies.First(); pair != nil; pair = pair.Next() {
+		if pair.Value().Content == nil {
+			continue
+		}
+		content := pair.Value().Content.GetOrZero("application/json")
+		if content == nil {
+			continue
+		}
+		models = append(models, b.NewModel(
+			componentName(pair.Key(), "RequestBody"),
+			"#/components/requestBodies/"+EscapeJSONPointer(pair.Key())+"/content/application~1json/schema",
+			content.Schema,
+		))
+	}
+	for pair := components.Responses.First(); pair != nil; pair = pair.Next() {
+		if pair.Value().Content == nil {
+			continue
+		}
+		content := pair.Value().Content.GetOrZero("application/json")
+		if content == nil || content.Schema == nil {
+			continue
+		}
+		models = append(models, b.NewModel(
+			componentName(pair.Key(), "Response"),
+			"#/components/responses/"+EscapeJSONPointer(pair.Key())+"/content/application~1json/schema",
+			content.Schema,
+		))
+	}
+	for pair := components.Headers.First(); pair != nil; pair = pair.Next() {
+		if pair.Value().Schema == nil {
+			continue
+		}
+		models = append(models, b.NewModel(
+			componentName(pair.Key(), "Header"),
+			"#/components/headers/"+EscapeJSONPointer(pair.Key())+"/schema",
+			pair.Value().Schema,
+		))
+	}
+	for _, model := range models {
+		model.(hasBase).base().named = true
+		b.share(model)
 	}
 	return models
 }
 
-func extractModelsFromPaths(paths *v3.Paths) []Model {
+// The name of a component that is not a schema, suffixed with its kind.
+func componentName(key, kind string) string {
+	if strings.HasSuffix(ToPascalCase(key), kind) {
+		return key
+	}
+	return key + "_" + kind
+}
+
+func (b *modelBuilder) extractModelsFromPaths(paths *v3.Paths) []Model {
 	if paths == nil {
 		return nil
 	}
 	var models []Model
 	for pair := paths.PathItems.First(); pair != nil; pair = pair.Next() {
+		location := "#/paths/" + EscapeJSONPointer(pair.Key())
 		pathItem := pair.Value()
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Get)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Put)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Post)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Delete)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Options)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Head)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Patch)...)
-		models = append(models, extractModelsFromOperation(pathItem.Parameters, pathItem.Trace)...)
+		models = append(models, b.extractModelsFromOperation(location, "get", pathItem.Parameters, pathItem.Get)...)
+		models = append(models, b.extractModelsFromOperation(location, "put", pathItem.Parameters, pathItem.Put)...)
+		models = append(models, b.extractModelsFromOperation(location, "post", pathItem.Parameters, pathItem.Post)...)
+		models = append(models, b.extractModelsFromOperation(location, "delete", pathItem.Parameters, pathItem.Delete)...)
+		models = append(models, b.extractModelsFromOperation(location, "options", pathItem.Parameters, pathItem.Options)...)
+		models = append(models, b.extractModelsFromOperation(location, "head", pathItem.Parameters, pathItem.Head)...)
+		models = append(models, b.extractModelsFromOperation(location, "patch", pathItem.Parameters, pathItem.Patch)...)
+		models = append(models, b.extractModelsFromOperation(location, "trace", pathItem.Parameters, pathItem.Trace)...)
 	}
 	return models
 }
 
-func extractModelsFromOperation(
-	pathItemParameters []*v3.Parameter, operation *v3.Operation,
+// The models of an operation, extracted once so that the operation refers to
+// the same models, with the same names, as the generated models.
+type operationModels struct {
+	requestBody Model
+	parameters  []parameterModel
+	responses   []responseModel
+}
+
+type parameterModel struct {
+	parameter *v3.Parameter
+	model     Model
+}
+
+type responseModel struct {
+	// The status code of the response, a range like 4XX, or default.
+	code  string
+	model Model
+}
+
+func (b *modelBuilder) extractModelsFromOperation(
+	pathLocation, method string, pathItemParameters []*v3.Parameter, operation *v3.Operation,
 ) []Model {
 	if operation == nil {
 		return nil
 	}
+	location := pathLocation + "/" + method
+	// The names of the models of an operation are derived from its ID.
+	if operation.OperationId == "" {
+		b.report(SeverityError, location, "operationId is empty")
+		return nil
+	}
+	extracted := operationModels{}
 	var models []Model
 	if operation.RequestBody != nil {
-		models = append(models, extractModelFromOperationRequestBody(operation))
+		extracted.requestBody = b.extractModelFromOperationRequestBody(location, operation)
+		if extracted.requestBody != nil {
+			models = append(models, extracted.requestBody)
+		}
+	}
+	extracted.parameters = append(
+		b.extractModelsFromParameters(location, operation.OperationId, operation.Parameters),
+		b.extractModelsFromParameters(pathLocation, operation.OperationId, pathItemParameters)...,
+	)
+	for _, parameter := range extracted.parameters {
+		models = append(models, parameter.model)
 	}
-	for _, pair := range extractModelsFromOperationParameters(
-		pathItemParameters, operation,
-	) {
-		models = append(models, pair.Value())
+	extracted.responses = b.extractModelsFromResponses(location, operation)
+	for _, response := range extracted.responses {
+		models = append(models, response.model)
 	}
+	b.operations[operation] = extracted
 	return models
 }
 
-func extractModelFromOperationRequestBody(operation *v3.Operation) Model {
-	content := operation.RequestBody.Content.GetOrZero("application/json")
+// Models the application/json content of the request body of an operation.
+// Other contents are not supported, so nil is returned with a diagnostic when
+// there is none.
+func (b *modelBuilder) extractModelFromOperationRequestBody(location string, operation *v3.Operation) Model {
+	var content *v3.MediaType
+	if operation.RequestBody.Content != nil {
+		content = operation.RequestBody.Content.GetOrZero("application/json")
+	}
 	if content == nil {
-		// TODO(GIA) Should it just ignore this operation? Return error?
-		panic(fmt.Sprintf(
+		b.report(SeverityError, location+"/requestBody",
 			"no application/json content for request body of operation %s",
 			operation.OperationId,
-		))
+		)
+		return nil
 	}
-	return NewModel(operation.OperationId+"RequestBody", content.Schema)
-}
-
-func extractModelsFromOperationParameters(
-	pathItemParameters []*v3.Parameter, operation *v3.Operation,
-) []orderedmap.Pair[string, Model] {
-	return append(
-		extractModelsFromParameters(operation.OperationId, operation.Parameters),
-		extractModelsFromParameters(operation.OperationId, pathItemParameters)...,
+	if content.Schema == nil {
+		b.report(SeverityError, location+"/requestBody/content/application~1json",
+			"no schema for the application/json content of the request body of operation %s",
+			operation.OperationId,
+		)
+		return nil
+	}
+	return b.NewModel(
+		operation.OperationId+"RequestBody",
+		location+"/requestBody/content/application~1json/schema",
+		content.Schema,
 	)
 }
 
-func extractModelsFromParameters(prefix string, parameters []*v3.Parameter) []orderedmap.Pair[string, Model] {
-	models := make([]orderedmap.Pair[string, Model], len(parameters))
+func (b *modelBuilder) extractModelsFromParameters(
+	location, prefix string, parameters []*v3.Parameter,
+) []parameterModel {
+	var models []parameterModel
 	for i, parameter := range parameters {
-		models[i] = orderedmap.NewPair(
-			ToCamelCase(parameter.Name),
-			NewModel(prefix+ToPascalCase(parameter.Name), parameter.Schema),
-		)
+		// Parameters serialized as a media type are not supported.
+		if parameter.Schema == nil {
+			pointer := fmt.Sprintf("%s/parameters/%d", location, i)
+			if parameter.Content != nil && parameter.Content.Len() > 0 {
+				b.report(SeverityError, pointer+"/content",
+					"parameter %s has content instead of a schema, which is not supported",
+					parameter.Name,
+				)
+			} else {
+				b.report(SeverityError, pointer, "parameter %s has no schema", parameter.Name)
+			}
+			continue
+		}
+		models = append(models, parameterModel{parameter, b.NewModel(
+			prefix+"_"+parameter.Name,
+			fmt.Sprintf("%s/parameters/%d/schema", location, i),
+			parameter.Schema,
+		)})
+	}
+	return models
+}
+
+// Models the application/json content of the responses of an operation, the
+// ones with a status code first and then the default one. Responses without
+// such content are skipped.
+func (b *modelBuilder) extractModelsFromResponses(location string, operation *v3.Operation) []responseModel {
+	if operation.Responses == nil {
+		return nil
+	}
+	var models []responseModel
+	extract := func(code string, response *v3.Response) {
+		if response == nil || response.Content == nil {
+			return
+		}
+		content := response.Content.GetOrZero("application/json")
+		if content == nil && response.Content.Len() > 0 {
+			b.report(SeverityWarning,
+				location+"/responses/"+EscapeJSONPointer(code)+"/content",
+				"no application/json content for response %s, its body is not typed nor validated",
+				code,
+			)
+		}
+		if content == nil || content.Schema == nil {
+			return
+		}
+		models = append(models, responseModel{code, b.NewModel(
+			operation.OperationId+ToPascalCase(code)+"Response",
+			location+"/responses/"+EscapeJSONPointer(code)+"/content/application~1json/schema",
+			content.Schema,
+		)})
+	}
+	for pair := operation.Responses.Codes.First(); pair != nil; pair = pair.Next() {
+		extract(pair.Key(), pair.Value())
 	}
+	extract("default", operation.Responses.Default)
 	return models
 }
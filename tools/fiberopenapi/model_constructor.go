@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// A constructor of an object model that takes its required properties as
+// arguments, in the order of the properties, and the optional ones as
+// functional options, like NewPet(name Name, opts ...PetOption) Pet. The
+// optional properties with a default value in the specification have it
+// unless an option sets them.
+func (m *objectModel) constructor() string {
+	var parameters, fields, defaults, options string
+	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
+		key, fieldName, fieldType := pair.Key(), m.fieldName(pair.Key()), m.fieldType(pair.Key())
+		if slices.Contains(m.schema.Required, key) {
+			parameter := constructorParameter(key)
+			parameters += fmt.Sprintf("%s %s, ", parameter, fieldType)
+			fields += fmt.Sprintf("%s: %s, ", fieldName, parameter)
+			continue
+		}
+		if value := pair.Value().Schema().Default; value != nil {
+			defaults += fmt.Sprintf("\n\tdecodeDefault(%s, &m.%s)",
+				jsonLiteral(m.location+"/properties/"+EscapeJSONPointer(key)+"/default", value),
+				fieldName,
+			)
+		}
+		set := "v"
+		if m.pointers[key] {
+			set = "&v"
+		}
+		options += fmt.Sprintf(`
+// With%[1]s%[2]s sets the optional property %[3]q.
+func With%[1]s%[2]s(v %[4]s) %[1]sOption {
+	return func(m *%[1]s) {
+		m.%[2]s = %[5]s
+	}
+}
+`,
+			m.name, fieldName, key, pair.Value().Name(), set,
+		)
+	}
+	fields = strings.TrimSuffix(fields, ", ")
+	return fmt.Sprintf(`
+// An option of New%[1]s that sets an optional property.
+type %[1]sOption func(*%[1]s)
+%[2]s
+// New%[1]s returns a %[1]s with its required properties.
+// The optional ones are set by the options or to their default values.
+func New%[1]s(%[3]sopts ...%[1]sOption) %[1]s {
+	m := %[1]s{%[4]s}%[5]s
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return m
+}
+`,
+		m.name, options, parameters, fields, defaults,
+	)
+}
+
+// The names that the constructor declares besides the model: the type of its
+// options, the constructor and the options themselves.
+func (m *objectModel) constructorNames() []string {
+	names := []string{m.name + "Option", "New" + m.name}
+	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
+		if !slices.Contains(m.schema.Required, pair.Key()) {
+			names = append(names, "With"+m.name+m.fieldName(pair.Key()))
+		}
+	}
+	return names
+}
+
+// The name of the constructor parameter of a required property, which must
+// not be a Go keyword nor clash with the variables of the constructor.
+func constructorParameter(key string) string {
+	parameter := ToCamelCase(key)
+	if token.IsKeyword(parameter) || parameter == "m" || parameter == "opts" || parameter == "opt" {
+		return parameter + "_"
+	}
+	return parameter
+}
+
+// Reports the default values of the optional properties of an object schema
+// that would not decode as their fields, as the constructor sets them.
+func (b *modelBuilder) checkDefaults(location string, schema *base.Schema) {
+	for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+		property := pair.Value().Schema()
+		if property == nil || property.Default == nil || slices.Contains(schema.Required, pair.Key()) {
+			continue
+		}
+		defaultLocation := location + "/properties/" + EscapeJSONPointer(pair.Key()) + "/default"
+		value, err := jsonValue(property.Default)
+		if err == nil {
+			err = valueMismatch(value, property)
+		}
+		if err != nil {
+			b.report(SeverityError, defaultLocation, "invalid default value: %v", err)
+		}
+	}
+}
+
+// Decodes a value of the specification as JSON, with its numbers as
+// json.Number.
+func jsonValue(node *yaml.Node) (any, error) {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	err = decoder.Decode(&value)
+	return value, err
+}
+
+// Why a JSON value would not decode as the model of a schema, or nil. Its
+// type must be one of the types of the schema, with integers for integer
+// schemas and numbers in the strings of decimal ones, recursively through
+// properties and items, and it must be the const or one of the enum values
+// of the schema if it has them. Existing Go types of x-go-type are not
+// checked.
+func valueMismatch(value any, schema *base.Schema) error {
+	if schema == nil || goType(schema) != "" {
+		return nil
+	}
+	if schema.Const != nil {
+		constant, err := jsonValue(schema.Const)
+		if err == nil && !reflect.DeepEqual(value, constant) {
+			return fmt.Errorf("%s is not the const %s", jsonText(value), jsonText(constant))
+		}
+	}
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(node *yaml.Node) bool {
+		enum, err := jsonValue(node)
+		return err == nil && reflect.DeepEqual(value, enum)
+	}) {
+		return fmt.Errorf("%s is not one of the enum values", jsonText(value))
+	}
+	types := SchemaTypes(schema)
+	if len(types) == 0 {
+		return nil
+	}
+	decimal := schema.Format == "decimal"
+	mismatch := fmt.Errorf("%s is not of type %s", jsonText(value), strings.Join(types, " or "))
+	switch typed := value.(type) {
+	case nil:
+		if !slices.Contains(types, "null") {
+			return mismatch
+		}
+	case bool:
+		if !slices.Contains(types, "boolean") {
+			return mismatch
+		}
+	case string:
+		if !slices.Contains(types, "string") && !(decimal && slices.Contains(types, "number")) {
+			return mismatch
+		}
+		if decimal && !isNumber(typed) {
+			return fmt.Errorf("%s is not a decimal number", jsonText(value))
+		}
+	case json.Number:
+		bits := 64
+		if schema.Format == "int32" {
+			bits = 32
+		}
+		_, err := strconv.ParseInt(typed.String(), 10, bits)
+		if !slices.Contains(types, "number") && !(decimal && slices.Contains(types, "string")) &&
+			!(slices.Contains(types, "integer") && err == nil) {
+			return mismatch
+		}
+	case map[string]any:
+		if !slices.Contains(types, "object") {
+			return mismatch
+		}
+		for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+			property, ok := typed[pair.Key()]
+			if !ok {
+				continue
+			}
+			if err := valueMismatch(property, pair.Value().Schema()); err != nil {
+				return fmt.Errorf("%s: %w", pair.Key(), err)
+			}
+		}
+	case []any:
+		if !slices.Contains(types, "array") {
+			return mismatch
+		}
+		if len(schema.PrefixItems) > 0 {
+			if schema.MinItems != nil {
+				if required := min(int(*schema.MinItems), len(schema.PrefixItems)); len(typed) < required {
+					return fmt.Errorf("%s has less than %d items", jsonText(value), required)
+				}
+			}
+			if schema.Items != nil && schema.Items.IsB() && !schema.Items.B && len(typed) > len(schema.PrefixItems) {
+				return fmt.Errorf("%s has more than %d items", jsonText(value), len(schema.PrefixItems))
+			}
+		}
+		for i, item := range typed {
+			var itemSchema *base.Schema
+			if i < len(schema.PrefixItems) {
+				itemSchema = schema.PrefixItems[i].Schema()
+			} else if schema.Items != nil && schema.Items.IsA() {
+				itemSchema = schema.Items.A.Schema()
+			}
+			if err := valueMismatch(item, itemSchema); err != nil {
+				return fmt.Errorf("%d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// The JSON text of a decoded value, for the messages of diagnostics.
+func jsonText(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectConstructor(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Constructors", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"pet": {
+					"type": "object",
+					"required": ["name", "type", "photoUrls"],
+					"properties": {
+						"name": {"type": "string"},
+						"status": {"type": "string", "default": "available"},
+						"type": {"type": "string"},
+						"photoUrls": {"type": "array", "items": {"type": "string"}},
+						"tags": {"type": "array", "items": {"type": "string"}}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	require.NotEmpty(t, modelTypes)
+	methods := modelTypes[0].Methods()
+	for _, expected := range []string{
+		"type PetOption func(*Pet)",
+		"// NewPet returns a Pet with its required properties.\n" +
+			"// The optional ones are set by the options or to their default values.\n" +
+			"func NewPet(",
+		"func NewPet(name Name, type_ Type, photoUrls PhotoUrls, opts ...PetOption) Pet {",
+		"m := Pet{Name: name, Type: type_, PhotoUrls: photoUrls}",
+		"decodeDefault(\"\\\"available\\\"\", &m.Status)",
+		"func WithPetStatus(v Status) PetOption {",
+		"m.Status = &v",
+		"func WithPetTags(v Tags) PetOption {",
+		"m.Tags = v",
+	} {
+		assert.Contains(t, methods, expected)
+	}
+}
+
+func TestDefaultValues(t *testing.T) {
+	testCases := map[string]struct {
+		property string
+		expected string
+	}{
+		"string":               {property: `{"type": "string", "default": "available"}`},
+		"integer":              {property: `{"type": "integer", "default": 20}`},
+		"integer as float":     {property: `{"type": "integer", "default": 20.0}`},
+		"decimal string":       {property: `{"type": "number", "format": "decimal", "default": "12.50"}`},
+		"nullable":             {property: `{"type": ["integer", "null"], "default": null}`},
+		"free-form":            {property: `{"default": {"a": [1]}}`},
+		"Go type":              {property: `{"type": "string", "x-go-type": "time.Time", "default": 1}`},
+		"enum":                 {property: `{"type": "string", "enum": ["a", "b"], "default": "b"}`},
+		"object":               {property: `{"type": "object", "properties": {"n": {"type": "integer"}}, "default": {"n": 1, "other": "x"}}`},
+		"tuple":                {property: `{"type": "array", "prefixItems": [{"type": "string"}], "default": ["a", 1]}`},
+		"string as integer":    {property: `{"type": "integer", "default": "20"}`, expected: `"20" is not of type integer`},
+		"fraction as integer":  {property: `{"type": "integer", "default": 2.5}`, expected: `2.5 is not of type integer`},
+		"too large for int32":  {property: `{"type": "integer", "format": "int32", "default": 3000000000}`, expected: `3000000000 is not of type integer`},
+		"invalid decimal":      {property: `{"type": "string", "format": "decimal", "default": "many"}`, expected: `"many" is not a decimal number`},
+		"null":                 {property: `{"type": "string", "default": null}`, expected: `null is not of type string`},
+		"not in the enum":      {property: `{"type": "string", "enum": ["a", "b"], "default": "c"}`, expected: `"c" is not one of the enum values`},
+		"not the const":        {property: `{"const": 1, "default": 2}`, expected: `2 is not the const 1`},
+		"nested property":      {property: `{"type": "object", "properties": {"n": {"type": "integer"}}, "default": {"n": true}}`, expected: `n: true is not of type integer`},
+		"item":                 {property: `{"type": "array", "items": {"type": "boolean"}, "default": [true, "no"]}`, expected: `1: "no" is not of type boolean`},
+		"too many tuple items": {property: `{"type": "array", "prefixItems": [{"type": "string"}], "items": false, "default": ["a", "b"]}`, expected: `["a","b"] has more than 1 items`},
+		"too few tuple items":  {property: `{"type": "array", "prefixItems": [{"type": "string"}], "minItems": 1, "default": []}`, expected: `[] has less than 1 items`},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec, err := loadOpenAPIDocument([]byte(`{
+				"openapi": "3.1.0",
+				"info": {"title": "Defaults", "version": "1.0.0"},
+				"paths": {},
+				"components": {
+					"schemas": {
+						"page": {"type": "object", "properties": {"size": `+tc.property+`}}
+					}
+				}
+			}`), ".")
+			require.NoError(t, err)
+
+			_, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+			if tc.expected == "" {
+				assert.Empty(t, diagnostics)
+				return
+			}
+			require.Len(t, diagnostics, 1)
+			assert.Equal(t, SeverityError, diagnostics[0].Severity)
+			assert.Equal(t, "#/components/schemas/page/properties/size/default", diagnostics[0].Pointer)
+			assert.Equal(t, "invalid default value: "+tc.expected, diagnostics[0].Message)
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestObjectModel(t *testing.T) {
@@ -27,8 +30,8 @@ func TestObjectModel(t *testing.T) {
 			}(),
 			expectedTypeDefinitions: map[string]string{
 				"Status": "struct {\n" +
-					"\tWinner Winner `json:\"winner\"`\n" +
-					"\tBoard Board `json:\"board\"`\n" +
+					"\tWinner *Winner `json:\"winner,omitempty\"`\n" +
+					"\tBoard *Board `json:\"board,omitempty\"`\n" +
 					"}",
 				"Winner": "string",
 				"Board":  "string",
@@ -37,7 +40,7 @@ func TestObjectModel(t *testing.T) {
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
-			model := newObjectModel(name, testCase.schema)
+			model := newModelBuilder(nil).newObjectModel(name, "#/components/schemas/"+name, testCase.schema)
 			assert.Equal(t, name, model.Name())
 			typeDefinitions := map[string]string{}
 			for _, modelType := range model.Types() {
@@ -47,3 +50,554 @@ func TestObjectModel(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectModelReadOnlyWriteOnly(t *testing.T) {
+	readOnly, writeOnly := true, true
+	schema := &base.Schema{Type: []string{"object"}}
+	schema.Properties = orderedmap.New[string, *base.SchemaProxy]()
+	schema.Properties.Set("id", base.CreateSchemaProxy(
+		&base.Schema{Type: []string{"integer"}, ReadOnly: &readOnly},
+	))
+	schema.Properties.Set("password", base.CreateSchemaProxy(
+		&base.Schema{Type: []string{"string"}, WriteOnly: &writeOnly},
+	))
+	schema.Properties.Set("name", base.CreateSchemaProxy(
+		&base.Schema{Type: []string{"string"}},
+	))
+	schema.Required = []string{"id", "password", "name"}
+
+	model := newModelBuilder(nil).newObjectModel("User", "#/components/schemas/User", schema)
+	// Required readOnly and writeOnly properties are still omitted, as they
+	// are only sent in one direction.
+	assert.Equal(t, "struct {\n"+
+		"\tId *Id `json:\"id,omitempty\"`\n"+
+		"\tPassword *Password `json:\"password,omitempty\"`\n"+
+		"\tName Name `json:\"name\"`\n"+
+		"}", model.Definition())
+	methods := model.Methods()
+	assert.True(t, strings.Contains(methods, "func (m *User) ValidateRequest() error"))
+	assert.True(t, strings.Contains(methods, `NewReadOnlyError("id")`))
+	// Clients must send the required writeOnly properties.
+	assert.True(t, strings.Contains(methods,
+		"if m.Password == nil {\n\t\terrs = append(errs, NewRequiredError(\"password\"))\n\t}"))
+	assert.False(t, strings.Contains(methods, `NewRequiredError("id")`))
+	assert.True(t, strings.Contains(methods, "func (m *User) OmitWriteOnly()"))
+	assert.True(t, strings.Contains(methods, "m.Password = nil"))
+	assert.False(t, strings.Contains(methods, "m.Name = nil"))
+	// Clients send writeOnly properties, so they are encoded as any other.
+	assert.False(t, strings.Contains(methods, "MarshalJSON"))
+	assert.Empty(t, model.Imports())
+}
+
+func TestNestedWriteOnlyModels(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Write only", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"user": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"},
+						"password": {"type": "string", "writeOnly": true}
+					}
+				},
+				"team": {
+					"type": "object",
+					"required": ["owner"],
+					"properties": {
+						"owner": {"$ref": "#/components/schemas/user"},
+						"deputy": {"$ref": "#/components/schemas/user"},
+						"members": {"type": "array", "items": {"$ref": "#/components/schemas/user"}},
+						"name": {"type": "string"}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	methods := map[string]string{}
+	for _, modelType := range modelTypes {
+		methods[modelType.Name()] = modelType.Methods()
+	}
+	assert.Contains(t, methods["Team"], `
+func (m *Team) OmitWriteOnly() {
+	m.Owner.OmitWriteOnly()
+	if m.Deputy != nil {
+		m.Deputy.OmitWriteOnly()
+	}
+	m.Members.OmitWriteOnly()
+}`)
+	assert.Contains(t, methods["Members"], `
+func (m Members) OmitWriteOnly() {
+	for i := range m {
+		m[i].OmitWriteOnly()
+	}
+}`)
+	assert.NotContains(t, methods["UserName"], "OmitWriteOnly")
+}
+
+func TestRecursiveModels(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Recursive", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"category": {
+					"type": "object",
+					"properties": {
+						"parent": {"$ref": "#/components/schemas/category"},
+						"children": {
+							"type": "array",
+							"items": {"$ref": "#/components/schemas/category"}
+						}
+					}
+				},
+				"employee": {
+					"type": "object",
+					"properties": {
+						"manager": {"$ref": "#/components/schemas/manager"}
+					}
+				},
+				"manager": {
+					"type": "object",
+					"properties": {
+						"boss": {"$ref": "#/components/schemas/employee"}
+					}
+				},
+				"comment": {
+					"type": "object",
+					"properties": {
+						"replies": {
+							"type": "array",
+							"items": {
+								"type": "object",
+								"properties": {
+									"replies": {"$ref": "#/components/schemas/comment/properties/replies"}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	typeDefinitions := map[string]string{}
+	for _, modelType := range modelTypes {
+		typeDefinitions[modelType.Name()] = modelType.Definition()
+	}
+	assert.Equal(t, map[string]string{
+		"Category": "struct {\n" +
+			"\tParent *Category `json:\"parent,omitempty\"`\n" +
+			"\tChildren Children `json:\"children,omitempty\"`\n" +
+			"}",
+		"Children": "[]Category",
+		"Employee": "struct {\n" +
+			"\tManager *Manager `json:\"manager,omitempty\"`\n" +
+			"}",
+		"Manager": "struct {\n" +
+			"\tBoss *Employee `json:\"boss,omitempty\"`\n" +
+			"}",
+		"Comment": "struct {\n" +
+			"\tReplies Replies `json:\"replies,omitempty\"`\n" +
+			"}",
+		"Replies": "[]RepliesItem",
+		"RepliesItem": "struct {\n" +
+			"\tReplies Replies `json:\"replies,omitempty\"`\n" +
+			"}",
+	}, typeDefinitions)
+}
+
+func TestChainedReferences(t *testing.T) {
+	// The invoice comes first, so that the money is modeled after the
+	// references to it.
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Chained", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"invoice": {
+					"type": "object",
+					"properties": {
+						"total": {"$ref": "#/components/schemas/order/properties/total"},
+						"lines": {"$ref": "#/components/schemas/order/properties/lines"}
+					}
+				},
+				"order": {
+					"type": "object",
+					"properties": {
+						"total": {"$ref": "#/components/schemas/money"},
+						"lines": {"$ref": "#/components/schemas/lines"}
+					}
+				},
+				"lines": {"$ref": "#/components/schemas/line-list"},
+				"line-list": {"type": "array", "items": {"type": "string"}},
+				"money": {
+					"type": "object",
+					"properties": {
+						"amount": {"type": "string"}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	typeDefinitions := map[string]string{}
+	for _, modelType := range modelTypes {
+		typeDefinitions[modelType.Name()] = modelType.Definition()
+	}
+	assert.Equal(t, map[string]string{
+		"Invoice": "struct {\n" +
+			"\tTotal *Money `json:\"total,omitempty\"`\n" +
+			"\tLines LineList `json:\"lines,omitempty\"`\n" +
+			"}",
+		"Order": "struct {\n" +
+			"\tTotal *Money `json:\"total,omitempty\"`\n" +
+			"\tLines LineList `json:\"lines,omitempty\"`\n" +
+			"}",
+		"LineList":     "[]LineListItem",
+		"LineListItem": "string",
+		"Money": "struct {\n" +
+			"\tAmount *Amount `json:\"amount,omitempty\"`\n" +
+			"}",
+		"Amount": "string",
+	}, typeDefinitions)
+}
+
+func TestModelExtensions(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Extensions", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"money": {
+					"type": "string",
+					"x-go-type": "money.Amount",
+					"x-go-type-import": {"path": "example.com/money/v2", "name": "money"}
+				},
+				"invoice": {
+					"type": "object",
+					"x-go-name": "Bill",
+					"required": ["total"],
+					"properties": {
+						"total": {"$ref": "#/components/schemas/money"},
+						"id": {"type": "string", "format": "uuid", "x-go-name": "ID"},
+						"discount": {"$ref": "#/components/schemas/money"},
+						"notes": {"type": "string", "x-go-type-skip-optional-pointer": true},
+						"secret": {"type": "string", "x-go-json-ignore": true},
+						"paid": {"type": "boolean", "x-omitempty": false},
+						"issued": {
+							"type": "string",
+							"x-go-type": "time.Time",
+							"x-go-type-import": "time"
+						}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	typeDefinitions := map[string]string{}
+	var imports []string
+	for _, modelType := range modelTypes {
+		typeDefinitions[modelType.Name()] = modelType.Definition()
+		imports = append(imports, modelType.Imports()...)
+	}
+	assert.Equal(t, map[string]string{
+		"Bill": "struct {\n" +
+			"\tTotal money.Amount `json:\"total\"`\n" +
+			"\tID *ID `json:\"id,omitempty\"`\n" +
+			"\tDiscount *money.Amount `json:\"discount,omitempty\"`\n" +
+			"\tNotes Notes `json:\"notes,omitempty\"`\n" +
+			"\tSecret *Secret `json:\"-\"`\n" +
+			"\tPaid *Paid `json:\"paid\"`\n" +
+			"\tIssued *time.Time `json:\"issued,omitempty\"`\n" +
+			"}",
+		"ID":     "string",
+		"Notes":  "string",
+		"Secret": "string",
+		"Paid":   "bool",
+	}, typeDefinitions)
+	assert.Equal(t, []string{
+		`money "example.com/money/v2"`,
+		`money "example.com/money/v2"`,
+		`"time"`,
+	}, imports)
+}
+
+func TestGoTypeImportConflicts(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Conflicts", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"failure": {
+					"type": "object",
+					"properties": {
+						"kind": {
+							"type": "string",
+							"x-go-type": "errors.Kind",
+							"x-go-type-import": "github.com/pkg/errors"
+						},
+						"payload": {
+							"type": "object",
+							"x-go-type": "json.Payload",
+							"x-go-type-import": {"path": "example.com/json", "name": "json"}
+						},
+						"amount": {
+							"type": "string",
+							"x-go-type": "money.Amount",
+							"x-go-type-import": "example.com/go-money"
+						}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	require.Len(t, modelTypes, 1)
+	// The packages with the names of the ones that the generated code uses
+	// are imported with another name, and the packages are imported with the
+	// names that the types use.
+	assert.Equal(t, "struct {\n"+
+		"\tKind *pkgerrors.Kind `json:\"kind,omitempty\"`\n"+
+		"\tPayload *examplecomjson.Payload `json:\"payload,omitempty\"`\n"+
+		"\tAmount *money.Amount `json:\"amount,omitempty\"`\n"+
+		"}", modelTypes[0].Definition())
+	assert.Equal(t, []string{
+		`pkgerrors "github.com/pkg/errors"`,
+		`examplecomjson "example.com/json"`,
+		`money "example.com/go-money"`,
+	}, modelTypes[0].Imports())
+}
+
+func TestUntypedAndUnionModels(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Unions", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"task": {
+					"required": ["metadata"],
+					"properties": {
+						"metadata": {},
+						"status": {"enum": ["open", "closed"]},
+						"error": {"type": ["string", "null"]},
+						"payload": {
+							"type": ["array", "object"],
+							"items": {"type": "integer"},
+							"properties": {"id": {"type": "string"}}
+						}
+					}
+				},
+				"nothing": {"type": "null"}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	typeDefinitions := map[string]string{}
+	for _, modelType := range modelTypes {
+		typeDefinitions[modelType.Name()] = modelType.Definition()
+	}
+	assert.Equal(t, map[string]string{
+		"Task": "struct {\n" +
+			"\tMetadata Metadata `json:\"metadata\"`\n" +
+			"\tStatus *Status `json:\"status,omitempty\"`\n" +
+			"\tError *Error `json:\"error,omitempty\"`\n" +
+			"\tPayload *Payload `json:\"payload,omitempty\"`\n" +
+			"}",
+		"Metadata":         "= RawValue",
+		"Status":           "string",
+		"Error":            "struct {\n\tvalue any\n}",
+		"Payload":          "struct {\n\tvalue any\n}",
+		"PayloadArray":     "[]PayloadArrayItem",
+		"PayloadArrayItem": "int",
+		"PayloadObject":    "struct {\n\tId *Id `json:\"id,omitempty\"`\n}",
+		"Id":               "string",
+		"Nothing":          "= Null",
+	}, typeDefinitions)
+
+	methods := map[string]string{}
+	for _, modelType := range modelTypes {
+		methods[modelType.Name()] = modelType.Methods()
+	}
+	assert.Contains(t, methods["Error"], "func (u *Error) String() (string, bool) {")
+	assert.Contains(t, methods["Error"], "func NewErrorAsNull() *Error {")
+	assert.Contains(t, methods["Payload"], "func NewPayloadAsArray(v PayloadArray) *Payload {")
+	assert.Contains(t, methods["Payload"], "func (u *Payload) Object() (PayloadObject, bool) {")
+}
+
+func TestTupleModel(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Tuples", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"point": {
+					"type": "array",
+					"prefixItems": [
+						{"type": "number", "format": "double"},
+						{"type": "number", "format": "double"},
+						{"type": "string"}
+					],
+					"minItems": 2,
+					"items": false
+				},
+				"row": {
+					"prefixItems": [{"type": "string"}],
+					"items": {"type": "integer"}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	typeDefinitions := map[string]string{}
+	methods := map[string]string{}
+	for _, modelType := range modelTypes {
+		typeDefinitions[modelType.Name()] = modelType.Definition()
+		methods[modelType.Name()] = modelType.Methods()
+	}
+	assert.Equal(t, map[string]string{
+		"Point": "struct {\n" +
+			"\tItem0 PointItem0\n" +
+			"\tItem1 PointItem1\n" +
+			"\tItem2 *PointItem2\n" +
+			"}",
+		"PointItem0": "float64",
+		"PointItem1": "float64",
+		"PointItem2": "string",
+		"Row": "struct {\n" +
+			"\tItem0 *RowItem0\n" +
+			"\tItems []RowItem\n" +
+			"}",
+		"RowItem0": "string",
+		"RowItem":  "int",
+	}, typeDefinitions)
+	assert.Contains(t, methods["Point"], "return NewMinItemsError(len(items), 2)")
+	assert.Contains(t, methods["Point"], "return NewItemsError(len(items), 3)")
+	assert.Contains(t, methods["Row"], "m.Items = make([]RowItem, len(items)-1)")
+}
+
+func TestNumberModels(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Numbers", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"ratio": {"type": "number"},
+				"price": {"type": "number", "format": "decimal"},
+				"amount": {"type": "string", "format": "decimal"},
+				"id": {"type": "integer", "format": "int64"},
+				"count": {"type": "integer", "format": "int32"}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	// Numbers without constraints have nothing to validate.
+	validate := func(name string) string {
+		return fmt.Sprintf(`
+// Validate checks the constraints of the specification, recursively.
+func (m *%s) Validate() error {
+	return nil
+}
+`,
+			name,
+		)
+	}
+	testCases := map[string]struct {
+		options     ModelOptions
+		definitions map[string]string
+		methods     map[string]string
+	}{
+		"default": {
+			definitions: map[string]string{
+				"Ratio":  "float64",
+				"Price":  "= Decimal",
+				"Amount": "= DecimalString",
+				"Id":     "int64",
+				"Count":  "int32",
+			},
+			methods: map[string]string{
+				"Ratio": validate("Ratio"),
+				"Id":    validate("Id"),
+				"Count": validate("Count"),
+			},
+		},
+		"int64 as string": {
+			options: ModelOptions{Int64AsString: true},
+			definitions: map[string]string{
+				"Ratio":  "float64",
+				"Price":  "= Decimal",
+				"Amount": "= DecimalString",
+				"Id":     "int64",
+				"Count":  "int32",
+			},
+			methods: map[string]string{
+				"Id": `
+// MarshalJSON encodes the integer as a JSON string, so that JavaScript clients
+// do not round it.
+func (m Id) MarshalJSON() ([]byte, error) {
+	return marshalInt64String(int64(m))
+}
+
+func (m *Id) UnmarshalJSON(data []byte) error {
+	if err := unmarshalInt64String(data, (*int64)(m)); err != nil {
+		return err
+	}
+	return nil
+}
+` + validate("Id"),
+				"Ratio": validate("Ratio"),
+				"Count": validate("Count"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			definitions := map[string]string{}
+			methods := map[string]string{}
+			modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, tc.options)
+			require.Empty(t, diagnostics)
+			for _, modelType := range modelTypes {
+				definitions[modelType.Name()] = modelType.Definition()
+				if modelType.Methods() != "" {
+					methods[modelType.Name()] = modelType.Methods()
+				}
+			}
+			assert.Equal(t, tc.definitions, definitions)
+			assert.Equal(t, tc.methods, methods)
+		})
+	}
+}
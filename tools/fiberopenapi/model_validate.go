@@ -0,0 +1,418 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// A Validate method that checks the constraints of the specification on a
+// model, recursively. The checks append the errors to errs.
+func validateMethod(name, checks string) string {
+	if checks == "" {
+		return fmt.Sprintf(`
+// Validate checks the constraints of the specification, recursively.
+func (m *%s) Validate() error {
+	return nil
+}
+`,
+			name,
+		)
+	}
+	return fmt.Sprintf(`
+// Validate checks the constraints of the specification, recursively.
+func (m *%s) Validate() error {
+	var errs []error%s
+	return errors.Join(errs...)
+}
+`,
+		name, checks,
+	)
+}
+
+// Runs the checks of the keywords validated against the JSON value of the
+// model, like const or if, on its encoding.
+func encodedValueChecks(checks string) string {
+	if checks == "" {
+		return ""
+	}
+	return `
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	v := RawValue(data)` + checks
+}
+
+// Whether a model has constraints to check, either its own or the ones of its
+// sub-models, and so a Validate method. Models are memoized, and the ones being
+// checked are assumed to need validation so that recursive models terminate.
+// References to types that are not generated, like RawValue or the builtin
+// types of union members, do not need validation.
+func needsValidation(model Model, memo map[Model]bool) bool {
+	if reference, ok := model.(*referenceModel); ok {
+		if reference.Target() == nil {
+			return false
+		}
+		model = reference.Target()
+	}
+	if result, ok := memo[model]; ok {
+		return result
+	}
+	memo[model] = true
+	var result bool
+	switch typed := model.(type) {
+	case *stringModel:
+		result = typed.checks != "" || scalarChecks(typed.name, "string", typed.schema) != ""
+	case *booleanModel:
+		result = typed.checks != "" || scalarChecks(typed.name, "bool", typed.schema) != ""
+	case *numberModel:
+		result = typed.checks != "" || scalarChecks(typed.name, typed.Definition(), typed.schema) != ""
+	case *rawModel:
+		result = typed.checks != ""
+	case *decimalModel:
+		result = typed.hasChecks()
+	case *objectModel:
+		result = typed.checks != ""
+		for property := range typed.properties.ValuesFromOldest() {
+			result = needsValidation(property, memo) || result
+		}
+	case *arrayModel:
+		result = typed.checks != "" || arrayChecks(typed.schema, nil, nil) != "" ||
+			needsValidation(typed.items, memo)
+	case *tupleModel:
+		result = typed.checks != ""
+		for _, item := range typed.prefixItems {
+			result = needsValidation(item, memo) || result
+		}
+		if typed.items != nil {
+			result = needsValidation(typed.items, memo) || result
+		}
+	case *unionModel:
+		for _, member := range typed.members {
+			result = needsValidation(member.model, memo) ||
+				unionScalarChecks(typed.name, member) != "" || result
+		}
+	}
+	memo[model] = result
+	return result
+}
+
+// Validates a nested model, prefixing its errors with where it is, like the
+// key of a property. The model is nil when absent if it is a pointer.
+func nestedValidation(value, prefix string, pointer bool) string {
+	check := fmt.Sprintf(`
+	if err := %s.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf(%s, err))
+	}`,
+		value, strconv.Quote(strings.ReplaceAll(prefix, "%", "%%")+": %w"),
+	)
+	if !pointer {
+		return check
+	}
+	return fmt.Sprintf("\n\tif %s != nil {%s\n\t}", value, strings.ReplaceAll(check, "\n", "\n\t"))
+}
+
+// The name of the variable with the compiled pattern of a string model.
+func patternVariable(name string) string {
+	return ToCamelCase(name) + "Pattern"
+}
+
+// The declaration of the variable with the compiled pattern of a string
+// model, if it has one. Patterns that Go does not support fail the extraction
+// of the model, see checkPattern.
+func patternDeclaration(name string, schema *base.Schema) string {
+	if schema.Pattern == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nvar %s = regexp.MustCompile(%s)\n",
+		patternVariable(name), strconv.Quote(schema.Pattern),
+	)
+}
+
+// The checks of the keywords of a string, number, integer or boolean model
+// that are validated on the Go value, like maxLength or minimum.
+func scalarChecks(name, goType string, schema *base.Schema) string {
+	var checks string
+	value := fmt.Sprintf("%s(*m)", goType)
+	if len(schema.Enum) > 0 {
+		var values []string
+		for _, node := range schema.Enum {
+			switch {
+			case goType == "string" && node.Tag == "!!str":
+				values = append(values, strconv.Quote(node.Value))
+			case goType == "bool" && node.Tag == "!!bool",
+				strings.HasPrefix(goType, "int") && node.Tag == "!!int",
+				strings.HasPrefix(goType, "float") && (node.Tag == "!!int" || node.Tag == "!!float"):
+				values = append(values, node.Value)
+			}
+		}
+		checks += fmt.Sprintf(`
+	if !slices.Contains([]%s{%s}, %s) {
+		errs = append(errs, NewEnumError(%s))
+	}`,
+			goType, strings.Join(values, ", "), value, value,
+		)
+	}
+	if goType == "string" {
+		return checks + stringChecks(name, schema)
+	}
+	if goType == "bool" {
+		return checks
+	}
+	return checks + numberChecks(schema, "float64(*m)",
+		func(operator, limit string) string {
+			return fmt.Sprintf("float64(*m) %s %s", operator, limit)
+		},
+		func(divisor string) string {
+			return fmt.Sprintf("!isMultipleOf(float64(*m), %s)", divisor)
+		},
+	)
+}
+
+// The checks of the bounds and multipleOf of a number model. The conditions
+// under which a bound is exceeded, like `float64(*m) < 0` for the "<" operator
+// and the limit 0, and a number is not a multiple of the divisor are written
+// by compare and notMultiple, and the errors report the value as a float64.
+func numberChecks(
+	schema *base.Schema, value string,
+	compare func(operator, limit string) string, notMultiple func(divisor string) string,
+) string {
+	var checks string
+	bound := func(limit float64, operator, constructor string) string {
+		return fmt.Sprintf(`
+	if %s {
+		errs = append(errs, %s(%s, %s))
+	}`,
+			compare(operator, formatFloat(limit)), constructor, value, formatFloat(limit),
+		)
+	}
+	if schema.Minimum != nil {
+		if schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsA() && schema.ExclusiveMinimum.A {
+			checks += bound(*schema.Minimum, "<=", "NewExclusiveMinimumError")
+		} else {
+			checks += bound(*schema.Minimum, "<", "NewMinimumError")
+		}
+	}
+	if schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsB() {
+		checks += bound(schema.ExclusiveMinimum.B, "<=", "NewExclusiveMinimumError")
+	}
+	if schema.Maximum != nil {
+		if schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsA() && schema.ExclusiveMaximum.A {
+			checks += bound(*schema.Maximum, ">=", "NewExclusiveMaximumError")
+		} else {
+			checks += bound(*schema.Maximum, ">", "NewMaximumError")
+		}
+	}
+	if schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsB() {
+		checks += bound(schema.ExclusiveMaximum.B, ">=", "NewExclusiveMaximumError")
+	}
+	if schema.MultipleOf != nil {
+		checks += fmt.Sprintf(`
+	if %s {
+		errs = append(errs, NewMultipleOfError(%s, %s))
+	}`,
+			notMultiple(formatFloat(*schema.MultipleOf)), value, formatFloat(*schema.MultipleOf),
+		)
+	}
+	return checks
+}
+
+// The checks of the bounds and multipleOf of a decimal model, that compare
+// the numbers exactly.
+func decimalChecks(schema *base.Schema) string {
+	return numberChecks(schema, "m.Float64()",
+		func(operator, limit string) string {
+			return fmt.Sprintf("cmpRat(m.Rat(), %q) %s 0", limit, operator)
+		},
+		func(divisor string) string {
+			return fmt.Sprintf("!isRatMultipleOf(m.Rat(), %q)", divisor)
+		},
+	)
+}
+
+func stringChecks(name string, schema *base.Schema) string {
+	var checks string
+	if schema.MinLength != nil {
+		checks += fmt.Sprintf(`
+	if n := len([]rune(*m)); n < %d {
+		errs = append(errs, NewMinLengthError(n, %d))
+	}`,
+			*schema.MinLength, *schema.MinLength,
+		)
+	}
+	if schema.MaxLength != nil {
+		checks += fmt.Sprintf(`
+	if n := len([]rune(*m)); n > %d {
+		errs = append(errs, NewMaxLengthError(n, %d))
+	}`,
+			*schema.MaxLength, *schema.MaxLength,
+		)
+	}
+	if schema.Pattern != "" {
+		checks += fmt.Sprintf(`
+	if !%s.MatchString(string(*m)) {
+		errs = append(errs, NewPatternError(string(*m), %s))
+	}`,
+			patternVariable(name), strconv.Quote(schema.Pattern),
+		)
+	}
+	return checks
+}
+
+// The checks of the keywords of an array model that are validated on the Go
+// value, like maxItems, followed by the validation of its items if they need
+// it.
+func arrayChecks(schema *base.Schema, items Model, memo map[Model]bool) string {
+	var checks string
+	if schema.MinItems != nil {
+		checks += fmt.Sprintf(`
+	if len(*m) < %d {
+		errs = append(errs, NewMinItemsError(len(*m), %d))
+	}`,
+			*schema.MinItems, *schema.MinItems,
+		)
+	}
+	if schema.MaxItems != nil {
+		checks += fmt.Sprintf(`
+	if len(*m) > %d {
+		errs = append(errs, NewMaxItemsError(len(*m), %d))
+	}`,
+			*schema.MaxItems, *schema.MaxItems,
+		)
+	}
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+		checks += `
+	if i, j, ok := duplicateItems(*m); ok {
+		errs = append(errs, NewUniqueItemsError(i, j))
+	}`
+	}
+	if items != nil && needsValidation(items, memo) {
+		checks += `
+	for i := range *m {
+		if err := (*m)[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%d: %w", i, err))
+		}
+	}`
+	}
+	return checks
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func (m *stringModel) validateMethod() string {
+	return patternDeclaration(m.name, m.schema) + validateMethod(m.name,
+		encodedValueChecks(m.checks)+scalarChecks(m.name, "string", m.schema),
+	)
+}
+
+func (m *booleanModel) validateMethod() string {
+	return validateMethod(m.name, encodedValueChecks(m.checks)+scalarChecks(m.name, "bool", m.schema))
+}
+
+func (m *numberModel) validateMethod() string {
+	return validateMethod(m.name,
+		encodedValueChecks(m.checks)+scalarChecks(m.name, m.Definition(), m.schema),
+	)
+}
+
+// Aliases of RawValue and Decimal, that do not have checks, use their Validate
+// method instead.
+func (m *rawModel) validateMethod() string {
+	if m.checks == "" {
+		return ""
+	}
+	return validateMethod(m.name, encodedValueChecks(m.checks))
+}
+
+func (m *decimalModel) validateMethod() string {
+	if !m.hasChecks() {
+		return ""
+	}
+	return validateMethod(m.name, encodedValueChecks(m.checks)+decimalChecks(m.schema))
+}
+
+func (m *objectModel) validateMethod() string {
+	memo := map[Model]bool{}
+	checks := encodedValueChecks(m.checks)
+	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
+		if needsValidation(pair.Value(), memo) {
+			checks += nestedValidation(
+				"m."+m.fieldName(pair.Key()), pair.Key(), m.pointers[pair.Key()],
+			)
+		}
+	}
+	return validateMethod(m.name, checks)
+}
+
+func (m *arrayModel) validateMethod() string {
+	return validateMethod(m.name,
+		encodedValueChecks(m.checks)+arrayChecks(m.schema, m.items, map[Model]bool{}),
+	)
+}
+
+func (m *tupleModel) validateMethod() string {
+	memo := map[Model]bool{}
+	checks := encodedValueChecks(m.checks)
+	for i, item := range m.prefixItems {
+		if needsValidation(item, memo) {
+			checks += nestedValidation(fmt.Sprintf("m.Item%d", i), strconv.Itoa(i), i >= m.required)
+		}
+	}
+	if m.items != nil && needsValidation(m.items, memo) {
+		checks += fmt.Sprintf(`
+	for i := range m.Items {
+		if err := m.Items[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%%d: %%w", %d+i, err))
+		}
+	}`,
+			len(m.prefixItems),
+		)
+	}
+	return validateMethod(m.name, checks)
+}
+
+// The checks of the keywords of a scalar member of a union, which is a builtin
+// Go type, on a pointer m to its value. Decimals and nulls are not builtin
+// types and have nothing to check.
+func unionScalarChecks(name string, member unionMember) string {
+	switch member.model.Name() {
+	case "string", "bool", "int", "int32", "int64", "float32", "float64":
+		return scalarChecks(name+member.kind, member.model.Name(), member.model.Schema())
+	}
+	return ""
+}
+
+// Validates the value of the union as the member that it is. Scalar members
+// are builtin Go types, so their keywords are checked here.
+func (m *unionModel) validateMethod() string {
+	memo := map[Model]bool{}
+	var declarations, cases string
+	for _, member := range m.members {
+		var checks string
+		if needsValidation(member.model, memo) {
+			checks = `
+	if err := v.Validate(); err != nil {
+		errs = append(errs, err)
+	}`
+		} else if scalar := unionScalarChecks(m.name, member); scalar != "" {
+			checks = "\n\tm := &v" + scalar
+			if member.model.Name() == "string" {
+				declarations += patternDeclaration(m.name+member.kind, member.model.Schema())
+			}
+		}
+		if checks != "" {
+			cases += fmt.Sprintf("\n\tcase %s:%s", member.model.Name(), checks)
+		}
+	}
+	if cases == "" {
+		return validateMethod(m.name, "")
+	}
+	return declarations + validateMethod(m.name,
+		fmt.Sprintf("\n\tswitch v := m.value.(type) {%s\n\t}", cases),
+	)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMethods(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Validate", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"pet": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string", "maxLength": 5, "pattern": "^[a-z]+$"},
+						"kind": {"type": "string", "enum": ["cat", "dog"]},
+						"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 30},
+						"tags": {
+							"type": "array",
+							"uniqueItems": true,
+							"items": {"type": "string"}
+						},
+						"nick": {"type": ["string", "null"], "minLength": 1},
+						"notes": {"type": "string"}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+	methods := map[string]string{}
+	var imports []string
+	for _, modelType := range modelTypes {
+		methods[modelType.Name()] = modelType.Methods()
+		imports = append(imports, modelType.Imports()...)
+	}
+	testCases := map[string]struct {
+		model    string
+		expected []string
+	}{
+		"nested": {
+			model: "Pet",
+			expected: []string{
+				"func (m *Pet) Validate() error {",
+				"if err := m.Name.Validate(); err != nil {",
+				"errs = append(errs, fmt.Errorf(\"name: %w\", err))",
+				"if m.Age != nil {\n\t\tif err := m.Age.Validate(); err != nil {",
+				"if err := m.Tags.Validate(); err != nil {",
+			},
+		},
+		"string": {
+			model: "Name",
+			expected: []string{
+				"var namePattern = regexp.MustCompile(\"^[a-z]+$\")",
+				"if n := len([]rune(*m)); n > 5 {",
+				"if !namePattern.MatchString(string(*m)) {",
+			},
+		},
+		"enum": {
+			model:    "Kind",
+			expected: []string{`if !slices.Contains([]string{"cat", "dog"}, string(*m)) {`},
+		},
+		"integer": {
+			model: "Age",
+			expected: []string{
+				"if float64(*m) < 0 {",
+				"errs = append(errs, NewExclusiveMaximumError(float64(*m), 30))",
+			},
+		},
+		"array": {
+			model:    "Tags",
+			expected: []string{"if i, j, ok := duplicateItems(*m); ok {"},
+		},
+		"union": {
+			model: "Nick",
+			expected: []string{
+				"switch v := m.value.(type) {\n\tcase string:\n\tm := &v",
+				"if n := len([]rune(*m)); n < 1 {",
+			},
+		},
+		"unconstrained": {
+			model:    "Notes",
+			expected: []string{"func (m *Notes) Validate() error {\n\treturn nil\n}"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, expected := range tc.expected {
+				assert.Contains(t, methods[tc.model], expected)
+			}
+		})
+	}
+	// Properties without constraints are not validated.
+	assert.NotContains(t, methods["Pet"], "m.Notes.Validate()")
+	assert.Contains(t, imports, `"regexp"`)
+}
+
+func TestInvalidPattern(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Validate", "version": "1.0.0"},
+		"paths": {},
+		"components": {"schemas": {"code": {"type": "string", "pattern": "^(?=a)"}}}
+	}`), ".")
+	require.NoError(t, err)
+
+	_, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	assert.EqualError(t, diagnostics,
+		"#/components/schemas/code/pattern: error parsing regexp: "+
+			"invalid or unsupported Perl syntax: `(?=`",
+	)
+}
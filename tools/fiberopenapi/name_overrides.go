@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// Go names set outside of the specification, for the specifications that
+// cannot have the x-go-name extension, like the ones of third parties.
+type NameOverrides struct {
+	// Names of the types of the component schemas, by key.
+	Schemas map[string]string `yaml:"schemas"`
+	// Names of the methods of the operations, by operationId.
+	Operations map[string]string `yaml:"operations"`
+}
+
+// Sets the names as the x-go-name extension of the schemas and operations,
+// replacing the one that they might have. Names of schemas or operations that
+// are not in the document are an error, as they are probably misspelled.
+func (o NameOverrides) Apply(spec *libopenapi.DocumentModel[v3.Document]) error {
+	var unknown []string
+	for key, name := range o.Schemas {
+		var schema *base.Schema
+		if spec.Model.Components != nil {
+			schema = spec.Model.Components.Schemas.GetOrZero(key).Schema()
+		}
+		if schema == nil {
+			unknown = append(unknown, "schema "+key)
+			continue
+		}
+		setExtension(&schema.Extensions, ExtensionGoName, name)
+	}
+	found := map[string]bool{}
+	for operation := range operationsOf(spec.Model.Paths) {
+		if name, ok := o.Operations[operation.OperationId]; ok {
+			setExtension(&operation.Extensions, ExtensionGoName, name)
+			found[operation.OperationId] = true
+		}
+	}
+	for operationID := range o.Operations {
+		if !found[operationID] {
+			unknown = append(unknown, "operation "+operationID)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("cannot override the names of unknown %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Sets a vendor extension to a string value.
+func setExtension(extensions **orderedmap.Map[string, *yaml.Node], name, value string) {
+	if *extensions == nil {
+		*extensions = orderedmap.New[string, *yaml.Node]()
+	}
+	(*extensions).Set(name, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameOverrides(t *testing.T) {
+	spec := []byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Names", "version": "1.0.0"},
+		"paths": {
+			"/pets": {
+				"get": {"operationId": "list-pets", "responses": {
+					"200": {"description": "The pets", "content": {"application/json": {
+						"schema": {"$ref": "#/components/schemas/pet-list"}
+					}}}
+				}}
+			}
+		},
+		"components": {"schemas": {
+			"pet-list": {"type": "array", "items": {"type": "string"}}
+		}}
+	}`)
+
+	t.Run("known names", func(t *testing.T) {
+		document, err := loadOpenAPIDocument(spec, ".")
+		require.NoError(t, err)
+		require.NoError(t, NameOverrides{
+			Schemas:    map[string]string{"pet-list": "Pets"},
+			Operations: map[string]string{"list-pets": "Pets"},
+		}.Apply(document))
+
+		operations, diagnostics := ExtractOperations(document, ModelOptions{})
+		require.Empty(t, diagnostics)
+		require.Len(t, operations, 1)
+		assert.Equal(t, "Pets", operations[0].Name)
+		modelTypes, diagnostics := ExtractModelTypesFromDocument(document, ModelOptions{})
+		require.Empty(t, diagnostics)
+		var names []string
+		for _, modelType := range modelTypes {
+			names = append(names, modelType.Name())
+		}
+		assert.Contains(t, names, "Pets")
+	})
+	t.Run("unknown names", func(t *testing.T) {
+		document, err := loadOpenAPIDocument(spec, ".")
+		require.NoError(t, err)
+		err = NameOverrides{
+			Schemas:    map[string]string{"pets": "Pets"},
+			Operations: map[string]string{"list-pet": "Pets", "add-pet": "Add"},
+		}.Apply(document)
+		assert.EqualError(t, err, "cannot override the names of unknown operation add-pet, operation list-pet, schema pets")
+	})
+}
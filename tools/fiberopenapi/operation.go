@@ -2,95 +2,302 @@ package main
 
 import (
 	"fmt"
+	"go/token"
+	"go/types"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 )
 
 type Parameter struct {
 	Name string
 	Type string
-	// TODO(GIA) Required bool
+	// Name of the parameter in the specification, from which the name of
+	// the Go variable is derived.
+	ParamName string
+	// Description of the parameter, with its examples.
+	Description string
+	Deprecated  bool
+	// Where the parameter is, path, query, header or cookie, and how it is
+	// serialized there, with the defaults of the specification applied.
+	In       string
+	Style    string
+	Explode  bool
+	Required bool
+}
+
+// A response of an operation with a JSON body.
+type Response struct {
+	// The status code, a range like 4XX, or default.
+	Code string
+	Type string
+	// Whether the body has writeOnly properties, that the handlers omit.
+	WriteOnly bool
+}
+
+// The name of the field of the response type of the client with the body of
+// the response, like JSON200, JSON4XX or JSONDefault.
+func (r Response) Field() string {
+	if r.Code == "default" {
+		return "JSONDefault"
+	}
+	return "JSON" + strings.ToUpper(r.Code)
 }
 
 type Operation struct {
-	Name        string
-	Method      string
-	Path        string
-	RequestBody string
-	Parameters  []Parameter
+	// The operationId of the specification, and the name of the Go method
+	// derived from it.
+	ID     string
+	Name   string
+	Method string
+	// The path in the Fiber format, like /pets/:id, and in the format of the
+	// specification, like /pets/{id}.
+	Path         string
+	PathTemplate string
+	RequestBody  string
+	Parameters   []Parameter
+	Responses    []Response
+	// The status codes of all the declared responses, with or without a body.
+	StatusCodes []string
+	// Documentation of the operation from the specification.
+	Summary                string
+	Description            string
+	RequestBodyDescription string
+	Tags                   []string
+	// The security requirements, like "api_key or oauth (read:pets)", and as
+	// alternatives of schemes with their scopes.
+	Security             string
+	SecurityRequirements []map[string][]string
+	ExternalDocs         string
+	Deprecated           bool
+	// The date of the x-sunset extension as an HTTP date, like
+	// "Wed, 31 Dec 2025 00:00:00 GMT".
+	Sunset string
+	// Import specs required by the types of the request body, the
+	// parameters and the responses.
+	Imports []string
 }
 
-func ExtractOperations(spec *libopenapi.DocumentModel[v3.Document]) []Operation {
+// Extracts the operations of the document, with the problems found in it. The
+// operations are only complete if none of the diagnostics is an error.
+func ExtractOperations(spec *libopenapi.DocumentModel[v3.Document], options ModelOptions) ([]Operation, Diagnostics) {
 	var operations []Operation
 
+	// Operations reference the models extracted from the document, with the
+	// same names as the generated models.
+	b := newModelBuilder(spec.Model.Components)
+	b.options = options
+	b.extractModelsFromDocument(spec)
+
 	for pair := spec.Model.Paths.PathItems.First(); pair != nil; pair = pair.Next() {
-		path := ToFiberPath(pair.Key())
+		path := pair.Key()
 		pathItem := pair.Value()
-		parameters := pathItem.Parameters
 
+		// The security of the document applies to the operations that do not
+		// set theirs.
+		extractOperation := func(path, method string, operation *v3.Operation) Operation {
+			result := b.extractOperation(path, method, operation)
+			if operation.Security == nil {
+				result.Security = securityDoc(spec.Model.Security)
+				result.SecurityRequirements = securityRequirements(spec.Model.Security)
+			}
+			return result
+		}
 		if pathItem.Get != nil {
 			operations = append(operations,
-				extractOperation(path, "Get", parameters, pathItem.Get),
+				extractOperation(path, "Get", pathItem.Get),
 			)
 		}
 		if pathItem.Put != nil {
 			operations = append(operations,
-				extractOperation(path, "Put", parameters, pathItem.Put),
+				extractOperation(path, "Put", pathItem.Put),
 			)
 		}
 		if pathItem.Post != nil {
 			operations = append(operations,
-				extractOperation(path, "Post", parameters, pathItem.Post),
+				extractOperation(path, "Post", pathItem.Post),
 			)
 		}
 		if pathItem.Delete != nil {
 			operations = append(operations,
-				extractOperation(path, "Delete", parameters, pathItem.Delete),
+				extractOperation(path, "Delete", pathItem.Delete),
 			)
 		}
 		if pathItem.Options != nil {
 			operations = append(operations,
-				extractOperation(path, "Options", parameters, pathItem.Options),
+				extractOperation(path, "Options", pathItem.Options),
 			)
 		}
 		if pathItem.Head != nil {
 			operations = append(operations,
-				extractOperation(path, "Head", parameters, pathItem.Head),
+				extractOperation(path, "Head", pathItem.Head),
 			)
 		}
 		if pathItem.Patch != nil {
 			operations = append(operations,
-				extractOperation(path, "Patch", parameters, pathItem.Patch),
+				extractOperation(path, "Patch", pathItem.Patch),
 			)
 		}
 		if pathItem.Trace != nil {
 			operations = append(operations,
-				extractOperation(path, "Trace", parameters, pathItem.Trace),
+				extractOperation(path, "Trace", pathItem.Trace),
 			)
 		}
 	}
 
-	return operations
+	// The operations without an ID were reported with their models.
+	operations = slices.DeleteFunc(operations, func(operation Operation) bool {
+		return operation.ID == ""
+	})
+	return operations, b.diagnostics
 }
 
-func extractOperation(path, method string, parameters []*v3.Parameter, operation *v3.Operation) Operation {
+func (b *modelBuilder) extractOperation(path, method string, operation *v3.Operation) Operation {
 	if operation.OperationId == "" {
-		panic(fmt.Sprintf("operationId is empty for %s %s", method, path))
+		return Operation{}
 	}
 	result := Operation{
-		Name:   ToPascalCase(operation.OperationId),
-		Method: method,
-		Path:   path,
+		ID:                   operation.OperationId,
+		Name:                 ToPascalCase(operation.OperationId),
+		Method:               method,
+		Path:                 ToFiberPath(path),
+		PathTemplate:         path,
+		Summary:              operation.Summary,
+		Description:          operation.Description,
+		Tags:                 operation.Tags,
+		Security:             securityDoc(operation.Security),
+		SecurityRequirements: securityRequirements(operation.Security),
+		ExternalDocs:         externalDocsDoc(operation.ExternalDocs),
+		Deprecated:           operation.Deprecated != nil && *operation.Deprecated,
+	}
+	if operation.Responses != nil {
+		for pair := operation.Responses.Codes.First(); pair != nil; pair = pair.Next() {
+			result.StatusCodes = append(result.StatusCodes, pair.Key())
+		}
+		if operation.Responses.Default != nil {
+			result.StatusCodes = append(result.StatusCodes, "default")
+		}
 	}
 	if operation.RequestBody != nil {
-		result.RequestBody = extractModelFromOperationRequestBody(operation).Name()
+		result.RequestBodyDescription = operation.RequestBody.Description
+	}
+	var sunset string
+	if GetExtension(operation.Extensions, ExtensionSunset, &sunset) {
+		date, err := httpDate(sunset)
+		if err != nil {
+			b.report(SeverityError,
+				"#/paths/"+EscapeJSONPointer(path)+"/"+strings.ToLower(method)+"/"+ExtensionSunset,
+				"invalid %s of operation %s: %v", ExtensionSunset, operation.OperationId, err,
+			)
+		}
+		result.Sunset = date
+	}
+	GetExtension(operation.Extensions, ExtensionGoName, &result.Name)
+	models := b.operations[operation]
+	if models.requestBody != nil {
+		result.RequestBody = models.requestBody.Name()
+		result.Imports = append(result.Imports, goTypeImports(models.requestBody)...)
+	}
+	var variables []string
+	for _, extracted := range models.parameters {
+		parameter := Parameter{
+			Name:      ToCamelCase(extracted.parameter.Name),
+			Type:      extracted.model.Name(),
+			ParamName: extracted.parameter.Name,
+			Description: strings.TrimSpace(extracted.parameter.Description + " " +
+				examplesDoc(extracted.parameter.Example, nil)),
+			Deprecated: extracted.parameter.Deprecated,
+			In:         extracted.parameter.In,
+			Style:      extracted.parameter.Style,
+			Required:   extracted.parameter.In == "path",
+		}
+		if parameter.Style == "" {
+			parameter.Style = "simple"
+			if parameter.In == "query" || parameter.In == "cookie" {
+				parameter.Style = "form"
+			}
+		}
+		parameter.Explode = parameter.Style == "form"
+		if extracted.parameter.Explode != nil {
+			parameter.Explode = *extracted.parameter.Explode
+		}
+		if extracted.parameter.Required != nil && *extracted.parameter.Required {
+			parameter.Required = true
+		}
+		if parameter.Deprecated {
+			parameter.Description = strings.TrimSpace("Deprecated. " + parameter.Description)
+		}
+		GetExtension(extracted.parameter.Extensions, ExtensionGoName, &parameter.Name)
+		parameter.Name = parameterVariable(parameter.Name, variables)
+		variables = append(variables, parameter.Name)
+		result.Parameters = append(result.Parameters, parameter)
+		result.Imports = append(result.Imports, goTypeImports(extracted.model)...)
 	}
-	for _, pair := range extractModelsFromOperationParameters(parameters, operation) {
-		result.Parameters = append(result.Parameters, Parameter{
-			Name: pair.Key(),
-			Type: pair.Value().Name(),
+	for _, extracted := range models.responses {
+		result.Responses = append(result.Responses, Response{
+			Code:      extracted.code,
+			Type:      extracted.model.Name(),
+			WriteOnly: hasWriteOnlyProperties(extracted.model.Schema(), nil),
 		})
+		result.Imports = append(result.Imports, goTypeImports(extracted.model)...)
 	}
 	return result
 }
+
+// The identifiers that the functions of the operations in the handlers and in
+// the client declare or use, which the parameters must not shadow.
+var operationIdentifiers = []string{
+	// Variables.
+	"c", "h", "ctx", "body", "err", "status", "path", "query", "headers",
+	"cookies", "req", "resp", "data", "result", "key", "values", "cookie",
+	// Imported packages.
+	"context", "errors", "fiber", "http", "json", "strings", "time", "url",
+	// Functions of the runtime.
+	"validate", "validateRequest", "validateResponse", "checkDepth",
+	"omitWriteOnly", "decodeParameter", "newRequest", "sendRequest",
+	"decodeResponse", "pathParameter", "addQueryParameter", "headerParameter",
+	"cookieParameter",
+}
+
+// The name of the Go variable of a parameter, which must not be a Go keyword
+// nor shadow a predeclared identifier, the identifiers of the functions of
+// the operations or the other parameters.
+func parameterVariable(name string, taken []string) string {
+	for token.IsKeyword(name) || types.Universe.Lookup(name) != nil ||
+		slices.Contains(operationIdentifiers, name) || slices.Contains(taken, name) {
+		name += "_"
+	}
+	return name
+}
+
+// The schemes of each alternative security requirement with their scopes. An
+// empty requirement makes the security optional.
+func securityRequirements(requirements []*base.SecurityRequirement) []map[string][]string {
+	var alternatives []map[string][]string
+	for _, requirement := range requirements {
+		schemes := map[string][]string{}
+		for pair := requirement.Requirements.First(); pair != nil; pair = pair.Next() {
+			schemes[pair.Key()] = pair.Value()
+		}
+		alternatives = append(alternatives, schemes)
+	}
+	return alternatives
+}
+
+// Formats a date, like 2025-12-31, or a date and time in RFC 3339 format as an
+// HTTP date.
+func httpDate(value string) (string, error) {
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		date, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%q is not a date", value)
+	}
+	return date.UTC().Format(http.TimeFormat), nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// Selects operations by their tags, their operationIds, which can be globs like
+// "admin-*", the prefixes of their paths, like "/admin", or whether they have
+// the x-internal extension. An operation matches the filter if it has any of
+// them.
+type OperationFilter struct {
+	Tags       []string `yaml:"tags"`
+	Operations []string `yaml:"operations"`
+	Paths      []string `yaml:"paths"`
+	Internal   bool     `yaml:"internal"`
+}
+
+func (f OperationFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && len(f.Operations) == 0 && len(f.Paths) == 0 && !f.Internal
+}
+
+func (f OperationFilter) Matches(pathTemplate string, operation *v3.Operation) bool {
+	for _, pattern := range f.Operations {
+		if matched, _ := path.Match(pattern, operation.OperationId); matched {
+			return true
+		}
+	}
+	for _, tag := range operation.Tags {
+		if slices.Contains(f.Tags, tag) {
+			return true
+		}
+	}
+	// Prefixes match whole segments, so /pet does not match /pets.
+	for _, prefix := range f.Paths {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if pathTemplate == prefix || strings.HasPrefix(pathTemplate, prefix+"/") {
+			return true
+		}
+	}
+	var internal bool
+	return f.Internal && GetExtension(operation.Extensions, ExtensionInternal, &internal) && internal
+}
+
+// Checks that the operationIds are valid globs.
+func (f OperationFilter) validate() error {
+	for _, pattern := range f.Operations {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid operation glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Removes from the document the operations that do not match the filter, or
+// the ones that do unless matching is true, and the paths left without
+// operations. An empty filter keeps every operation.
+func (f OperationFilter) keep(spec *libopenapi.DocumentModel[v3.Document], matching bool) {
+	if f.IsEmpty() || spec.Model.Paths == nil {
+		return
+	}
+	var emptyPaths []string
+	for pair := spec.Model.Paths.PathItems.First(); pair != nil; pair = pair.Next() {
+		empty := true
+		for _, slot := range operationSlots(pair.Value()) {
+			if *slot == nil {
+				continue
+			}
+			if f.Matches(pair.Key(), *slot) != matching {
+				*slot = nil
+				continue
+			}
+			empty = false
+		}
+		if empty {
+			emptyPaths = append(emptyPaths, pair.Key())
+		}
+	}
+	for _, path := range emptyPaths {
+		spec.Model.Paths.PathItems.Delete(path)
+	}
+}
+
+// The fields of the operations of a path item, in the order in which they are
+// generated.
+func operationSlots(pathItem *v3.PathItem) []**v3.Operation {
+	return []**v3.Operation{
+		&pathItem.Get, &pathItem.Put, &pathItem.Post, &pathItem.Delete,
+		&pathItem.Options, &pathItem.Head, &pathItem.Patch, &pathItem.Trace,
+	}
+}
+
+// The operations of all the paths.
+func operationsOf(paths *v3.Paths) iter.Seq[*v3.Operation] {
+	return func(yield func(*v3.Operation) bool) {
+		if paths == nil {
+			return
+		}
+		for pair := paths.PathItems.First(); pair != nil; pair = pair.Next() {
+			for _, slot := range operationSlots(pair.Value()) {
+				if *slot != nil && !yield(*slot) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationFilter(t *testing.T) {
+	spec := []byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Filter", "version": "1.0.0"},
+		"paths": {
+			"/pets": {
+				"get": {"operationId": "list-pets", "tags": ["pets"], "responses": {}},
+				"post": {"operationId": "add-pet", "tags": ["pets", "admin"], "responses": {}}
+			},
+			"/stats": {
+				"get": {"operationId": "get-stats", "tags": ["admin"], "responses": {}}
+			},
+			"/stats/internal": {
+				"get": {"operationId": "get-internal-stats", "x-internal": true, "responses": {}}
+			}
+		}
+	}`)
+	tests := map[string]struct {
+		include  OperationFilter
+		exclude  OperationFilter
+		expected []string
+		paths    []string
+	}{
+		"no filters": {
+			expected: []string{"list-pets", "add-pet", "get-stats", "get-internal-stats"},
+			paths:    []string{"/pets", "/stats", "/stats/internal"},
+		},
+		"include tags": {
+			include:  OperationFilter{Tags: []string{"pets"}},
+			expected: []string{"list-pets", "add-pet"},
+			paths:    []string{"/pets"},
+		},
+		"exclude tags": {
+			exclude:  OperationFilter{Tags: []string{"admin"}},
+			expected: []string{"list-pets", "get-internal-stats"},
+			paths:    []string{"/pets", "/stats/internal"},
+		},
+		"operation globs": {
+			include:  OperationFilter{Operations: []string{"get-*"}},
+			expected: []string{"get-stats", "get-internal-stats"},
+			paths:    []string{"/stats", "/stats/internal"},
+		},
+		"path prefixes": {
+			include:  OperationFilter{Paths: []string{"/stats/"}},
+			expected: []string{"get-stats", "get-internal-stats"},
+			paths:    []string{"/stats", "/stats/internal"},
+		},
+		"path prefixes of whole segments": {
+			include:  OperationFilter{Paths: []string{"/pet", "/stats/internal"}},
+			expected: []string{"get-internal-stats"},
+			paths:    []string{"/stats/internal"},
+		},
+		"exclude internal": {
+			exclude:  OperationFilter{Internal: true},
+			expected: []string{"list-pets", "add-pet", "get-stats"},
+			paths:    []string{"/pets", "/stats"},
+		},
+		"include and exclude": {
+			include:  OperationFilter{Tags: []string{"admin"}},
+			exclude:  OperationFilter{Operations: []string{"add-pet"}},
+			expected: []string{"get-stats"},
+			paths:    []string{"/stats"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := loadOpenAPIDocument(spec, ".")
+			require.NoError(t, err)
+			test.include.keep(document, true)
+			test.exclude.keep(document, false)
+
+			var operationIDs []string
+			for operation := range operationsOf(document.Model.Paths) {
+				operationIDs = append(operationIDs, operation.OperationId)
+			}
+			assert.Equal(t, test.expected, operationIDs)
+			// Paths without operations are removed.
+			paths := slices.Collect(document.Model.Paths.PathItems.KeysFromOldest())
+			assert.Equal(t, test.paths, paths)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPDate(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		expected string
+		err      bool
+	}{
+		"date":      {value: "2026-12-31", expected: "Thu, 31 Dec 2026 00:00:00 GMT"},
+		"date time": {value: "2026-12-31T12:00:00+02:00", expected: "Thu, 31 Dec 2026 10:00:00 GMT"},
+		"invalid":   {value: "next year", err: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			date, err := httpDate(tc.value)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, date)
+		})
+	}
+}
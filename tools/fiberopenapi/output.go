@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// The permissions of the generated files.
+const generatedFileMode = 0o644
+
+// Where the generated files go. They are written atomically, and only when
+// their content changes, so that the build caches of the packages that did not
+// change stay valid. In check mode, nothing is written, and the files are
+// compared with the ones on disk instead. A nil Output writes the files.
+type Output struct {
+	// Compare the generated files with the ones on disk instead of writing
+	// them.
+	Check bool
+	// The unified diffs of the files on disk that differ from the generated
+	// ones, in check mode.
+	Diffs []string
+}
+
+// Writes a generated file, unless it has the same content already. In check
+// mode, the diff with the file on disk is recorded instead.
+func (o *Output) WriteFile(path string, content []byte) error {
+	current, err := os.ReadFile(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	if exists && bytes.Equal(current, content) {
+		return nil
+	}
+	if o != nil && o.Check {
+		o.Diffs = append(o.Diffs, unifiedDiff(path, exists, current, content))
+		return nil
+	}
+	return writeFileAtomically(path, content)
+}
+
+// Writes a file through a temporary one in the same directory that replaces
+// it, so that the file is never left half written. The directory is created
+// if it does not exist.
+func writeFileAtomically(path string, content []byte) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	if err := file.Chmod(generatedFileMode); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	return nil
+}
+
+// The unified diff from a file on disk, that might not exist, to its generated
+// content.
+func unifiedDiff(path string, exists bool, current, generated []byte) string {
+	from := path
+	if !exists {
+		from = "/dev/null"
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        lines(current),
+		B:        lines(generated),
+		FromFile: from,
+		ToFile:   path + " (generated)",
+		Context:  3,
+	})
+	return diff
+}
+
+// The lines of a file, each one with its line break.
+func lines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputWriteFile(t *testing.T) {
+	// A time in the past, to tell whether a file is written again.
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tests := map[string]struct {
+		// Content of the file on disk, which does not exist when empty.
+		current   string
+		check     bool
+		generated string
+		expected  string
+		written   bool
+		diff      string
+	}{
+		"new file": {
+			generated: "package pets\n",
+			expected:  "package pets\n",
+			written:   true,
+		},
+		"changed file": {
+			current:   "package pets\n\ntype Pet string\n",
+			generated: "package pets\n\ntype Pet struct{}\n",
+			expected:  "package pets\n\ntype Pet struct{}\n",
+			written:   true,
+		},
+		"unchanged file": {
+			current:   "package pets\n",
+			generated: "package pets\n",
+			expected:  "package pets\n",
+		},
+		"check stale file": {
+			current:   "package pets\n\ntype Pet string\n",
+			check:     true,
+			generated: "package pets\n\ntype Pet struct{}\n",
+			expected:  "package pets\n\ntype Pet string\n",
+			diff: "--- pets.go\n+++ pets.go (generated)\n" +
+				"@@ -1,3 +1,3 @@\n package pets\n \n-type Pet string\n+type Pet struct{}\n",
+		},
+		"check missing file": {
+			check:     true,
+			generated: "package pets\n",
+			diff:      "--- /dev/null\n+++ pets.go (generated)\n@@ -0,0 +1 @@\n+package pets\n",
+		},
+		"check up to date file": {
+			current:   "package pets\n",
+			check:     true,
+			generated: "package pets\n",
+			expected:  "package pets\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "pets.go")
+			if test.current != "" {
+				require.NoError(t, os.WriteFile(path, []byte(test.current), 0o600))
+				require.NoError(t, os.Chtimes(path, past, past))
+			}
+
+			output := &Output{Check: test.check}
+			require.NoError(t, output.WriteFile(path, []byte(test.generated)))
+
+			if test.diff == "" {
+				assert.Empty(t, output.Diffs)
+			} else {
+				require.Len(t, output.Diffs, 1)
+				assert.Equal(t, test.diff, strings.ReplaceAll(output.Diffs[0], path, "pets.go"))
+			}
+			info, err := os.Stat(path)
+			if test.current == "" && test.check {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(content))
+			assert.Equal(t, test.written, !info.ModTime().Equal(past))
+			if test.written {
+				assert.Equal(t, os.FileMode(generatedFileMode), info.Mode().Perm())
+			}
+			// No temporary files are left behind.
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, 1)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+var pathItemMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Removes from the document the components that models are generated for,
+// like schemas, that its operations do not refer to, directly or through other
+// components. Models are then only generated for the operations that the
+// filters keep.
+func pruneComponents(spec *libopenapi.DocumentModel[v3.Document]) {
+	components := spec.Model.Components
+	if components == nil || spec.Index == nil || spec.Index.GetRootNode() == nil {
+		return
+	}
+	root := spec.Index.GetRootNode()
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	reachable := map[string]bool{}
+	if spec.Model.Paths != nil {
+		for pair := spec.Model.Paths.PathItems.First(); pair != nil; pair = pair.Next() {
+			pathItem := pair.Value()
+			if pathItem.GoLow() == nil || pathItem.GoLow().RootNode == nil {
+				continue
+			}
+			// The operations that the filters removed are still in the nodes
+			// of the path item.
+			operations := pathItem.GetOperations()
+			node := pathItem.GoLow().RootNode
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				method := node.Content[i].Value
+				if _, ok := operations.Get(method); !ok && slices.Contains(pathItemMethods, method) {
+					continue
+				}
+				collectComponents(root, node.Content[i+1], reachable)
+			}
+		}
+	}
+	pruneComponentMap(components.Schemas, "schemas", reachable)
+	pruneComponentMap(components.Parameters, "parameters", reachable)
+	pruneComponentMap(components.RequestBodies, "requestBodies", reachable)
+	pruneComponentMap(components.Responses, "responses", reachable)
+	pruneComponentMap(components.Headers, "headers", reachable)
+}
+
+// Collects the components that a node refers to, like
+// "#/components/schemas/Pet", and the ones that they refer to in turn.
+func collectComponents(root, node *yaml.Node, reachable map[string]bool) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				reachComponent(root, value.Value, reachable)
+			case key == "discriminator":
+				// The mapping of a discriminator refers to schemas by
+				// reference or by name.
+				mapping := mappingValue(value, "mapping")
+				for j := 1; mapping != nil && j < len(mapping.Content); j += 2 {
+					reference := mapping.Content[j].Value
+					if !strings.Contains(reference, "/") {
+						reference = "#/components/schemas/" + EscapeJSONPointer(reference)
+					}
+					reachComponent(root, reference, reachable)
+				}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		collectComponents(root, child, reachable)
+	}
+}
+
+// Marks the component of a local reference as reachable, even when the
+// reference points inside of it, and collects the ones that it refers to.
+func reachComponent(root *yaml.Node, reference string, reachable map[string]bool) {
+	if !strings.HasPrefix(reference, "#/components/") {
+		return
+	}
+	segments := strings.SplitN(strings.TrimPrefix(reference, "#/components/"), "/", 3)
+	if len(segments) < 2 {
+		return
+	}
+	component := "#/components/" + segments[0] + "/" + segments[1]
+	if reachable[component] {
+		return
+	}
+	reachable[component] = true
+	if node := resolvePointer(root, strings.TrimPrefix(component, "#")); node != nil {
+		collectComponents(root, node, reachable)
+	}
+}
+
+func pruneComponentMap[V any](components *orderedmap.Map[string, V], kind string, reachable map[string]bool) {
+	if components == nil {
+		return
+	}
+	var unreachable []string
+	for pair := components.First(); pair != nil; pair = pair.Next() {
+		if !reachable["#/components/"+kind+"/"+EscapeJSONPointer(pair.Key())] {
+			unreachable = append(unreachable, pair.Key())
+		}
+	}
+	for _, name := range unreachable {
+		components.Delete(name)
+	}
+}
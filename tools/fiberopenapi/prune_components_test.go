@@ -0,0 +1,88 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneComponents(t *testing.T) {
+	spec := []byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Prune", "version": "1.0.0"},
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "list-pets",
+					"tags": ["pets"],
+					"parameters": [{"$ref": "#/components/parameters/limit"}],
+					"responses": {"200": {"$ref": "#/components/responses/Pets"}}
+				}
+			},
+			"/orders": {
+				"post": {
+					"operationId": "place-order",
+					"tags": ["store"],
+					"requestBody": {"$ref": "#/components/requestBodies/Order"},
+					"responses": {"204": {"description": "Placed"}}
+				}
+			}
+		},
+		"components": {
+			"parameters": {
+				"limit": {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+			},
+			"requestBodies": {
+				"Order": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Order"}}}}
+			},
+			"responses": {
+				"Pets": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}
+			},
+			"schemas": {
+				"Pet": {
+					"oneOf": [{"$ref": "#/components/schemas/Cat"}, {"$ref": "#/components/schemas/Dog"}],
+					"discriminator": {"propertyName": "kind", "mapping": {"cat": "Cat", "dog": "#/components/schemas/Dog"}}
+				},
+				"Cat": {"type": "object", "properties": {"owner": {"$ref": "#/components/schemas/Owner"}}},
+				"Dog": {"type": "object"},
+				"Owner": {"type": "object"},
+				"Order": {"type": "object", "properties": {"pet": {"$ref": "#/components/schemas/Pet"}}},
+				"Unused": {"type": "object"}
+			}
+		}
+	}`)
+	tests := map[string]struct {
+		include  OperationFilter
+		expected []string
+	}{
+		"all operations": {
+			include:  OperationFilter{Tags: []string{"pets", "store"}},
+			expected: []string{"limit", "Order", "Pets", "Pet", "Cat", "Dog", "Owner", "Order"},
+		},
+		"references of references": {
+			include:  OperationFilter{Tags: []string{"pets"}},
+			expected: []string{"limit", "Pets", "Pet", "Cat", "Dog", "Owner"},
+		},
+		"no operations": {
+			include: OperationFilter{Tags: []string{"admin"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := loadOpenAPIDocument(spec, ".")
+			require.NoError(t, err)
+			test.include.keep(document, true)
+			pruneComponents(document)
+
+			components := document.Model.Components
+			var names []string
+			names = slices.AppendSeq(names, components.Parameters.KeysFromOldest())
+			names = slices.AppendSeq(names, components.RequestBodies.KeysFromOldest())
+			names = slices.AppendSeq(names, components.Responses.KeysFromOldest())
+			names = slices.AppendSeq(names, components.Schemas.KeysFromOldest())
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
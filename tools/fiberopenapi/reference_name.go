@@ -0,0 +1,39 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// Segments of a JSON pointer that do not name a schema.
+var referenceKeywords = map[string]bool{
+	"schema":     true,
+	"content":    true,
+	"components": true,
+	"schemas":    true,
+	"$defs":      true,
+}
+
+// Derives the name of the schema that a reference points to, using the last
+// segment of its JSON pointer that names the schema. For example
+// "./common.yaml#/components/schemas/Money" becomes "Money" and
+// "#/components/parameters/limit/schema" becomes "limit". References to a
+// whole file are named after the file.
+func ReferenceName(reference string) string {
+	file, pointer, _ := strings.Cut(reference, "#")
+	segments := strings.Split(strings.Trim(pointer, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := strings.ReplaceAll(segments[i], "~1", "/")
+		segment = strings.ReplaceAll(segment, "~0", "~")
+		switch {
+		case segment == "" || referenceKeywords[segment] || strings.Contains(segment, "/"):
+			// Keywords and media types.
+			continue
+		case segment == "items":
+			// Same name as the items of an array model.
+			return ReferenceName(file+"#/"+strings.Join(segments[:i], "/")) + "_item"
+		}
+		return segment
+	}
+	return strings.TrimSuffix(path.Base(file), path.Ext(file))
+}
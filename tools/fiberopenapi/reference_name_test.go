@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestReferenceName(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"component schema": {
+			input:    "#/components/schemas/pet-status",
+			expected: "pet-status",
+		},
+		"schema in another file": {
+			input:    "./common.yaml#/components/schemas/Money",
+			expected: "Money",
+		},
+		"property of a schema": {
+			input:    "#/components/schemas/Comment/properties/replies",
+			expected: "replies",
+		},
+		"items of a schema": {
+			input:    "#/components/schemas/Comment/properties/replies/items",
+			expected: "replies_item",
+		},
+		"schema of a parameter": {
+			input:    "#/components/parameters/limit/schema",
+			expected: "limit",
+		},
+		"schema of a request body": {
+			input:    "#/components/requestBodies/NewPet/content/application~1json/schema",
+			expected: "NewPet",
+		},
+		"definition": {
+			input:    "#/$defs/node",
+			expected: "node",
+		},
+		"whole file": {
+			input:    "../schemas/money-amount.yaml",
+			expected: "money-amount",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := ReferenceName(tc.input)
+			if result != tc.expected {
+				t.Errorf("ReferenceName(%q) = %q, want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
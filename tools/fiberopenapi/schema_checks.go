@@ -0,0 +1,407 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// Checks the keywords of a schema that are not supported, failing with the
+// location of the schema in the specification. additionalProperties is only
+// supported when it allows any property, as the structs of objects ignore the
+// unknown ones.
+func checkSupportedKeywords(location string, schema *base.Schema) {
+	keywords := []struct {
+		name string
+		set  bool
+	}{
+		{"allOf", len(schema.AllOf) > 0},
+		{"anyOf", len(schema.AnyOf) > 0},
+		{"oneOf", len(schema.OneOf) > 0},
+		{"not", schema.Not != nil},
+		{"additionalProperties", schema.AdditionalProperties != nil && !allowsAnyProperty(schema.AdditionalProperties)},
+		{"patternProperties", schema.PatternProperties != nil && schema.PatternProperties.Len() > 0},
+		{"propertyNames", schema.PropertyNames != nil},
+		{"unevaluatedItems", schema.UnevaluatedItems != nil},
+		{"$dynamicRef", SchemaKeyword(schema, "$dynamicRef") != nil},
+	}
+	for _, keyword := range keywords {
+		if keyword.set {
+			failf(location, "keyword %s is not supported", keyword.name)
+		}
+	}
+}
+
+// Whether additionalProperties allows any property, being true or {}.
+func allowsAnyProperty(additional *base.DynamicValue[*base.SchemaProxy, bool]) bool {
+	if additional.IsB() {
+		return additional.B
+	}
+	node := additional.A.GetValueNode()
+	return node != nil && node.Kind == yaml.MappingNode && len(node.Content) == 0
+}
+
+// Checks that the pattern of a schema, if it has one, is a regular expression
+// that Go supports, as it is compiled by the generated code.
+func checkPattern(location string, schema *base.Schema) {
+	if schema.Pattern == "" {
+		return
+	}
+	if _, err := regexp.Compile(schema.Pattern); err != nil {
+		failf(location+"/pattern", "%v", err)
+	}
+}
+
+// Statements that check the keywords of a schema that are validated against
+// the JSON value v of a model once decoded, like const or if. The errors are
+// appended to errs.
+func valueChecks(location string, schema *base.Schema) string {
+	var checks string
+	if schema.Const != nil {
+		value := jsonLiteral(location+"/const", schema.Const)
+		checks += fmt.Sprintf(`
+	if !v.Equal(%s) {
+		errs = append(errs, NewConstError(data, %s))
+	}`,
+			value, value,
+		)
+	}
+	if schema.Contains != nil {
+		checks += containsChecks(location, schema)
+	}
+	if schema.MinProperties != nil {
+		checks += fmt.Sprintf(`
+	if n := len(v.Keys()); v.Is("object") && n < %d {
+		errs = append(errs, NewMinPropertiesError(n, %d))
+	}`,
+			*schema.MinProperties, *schema.MinProperties,
+		)
+	}
+	if schema.MaxProperties != nil {
+		checks += fmt.Sprintf(`
+	if n := len(v.Keys()); v.Is("object") && n > %d {
+		errs = append(errs, NewMaxPropertiesError(n, %d))
+	}`,
+			*schema.MaxProperties, *schema.MaxProperties,
+		)
+	}
+	dependentRequired, err := DependentRequired(schema)
+	if err != nil {
+		failf(location, "%v", err)
+	}
+	if dependentRequired != nil {
+		for pair := dependentRequired.First(); pair != nil; pair = pair.Next() {
+			for _, dependency := range pair.Value() {
+				checks += fmt.Sprintf(`
+	if v.Has(%q) && !v.Has(%q) {
+		errs = append(errs, NewDependentRequiredError(%q, %q))
+	}`,
+					pair.Key(), dependency, pair.Key(), dependency,
+				)
+			}
+		}
+	}
+	if schema.DependentSchemas != nil {
+		for pair := schema.DependentSchemas.First(); pair != nil; pair = pair.Next() {
+			checks += fmt.Sprintf(`
+	if v.Has(%q) && !%s(v) {
+		errs = append(errs, NewDependentSchemasError(%q))
+	}`,
+				pair.Key(),
+				predicate(location+"/dependentSchemas/"+EscapeJSONPointer(pair.Key()), pair.Value(), nil),
+				pair.Key(),
+			)
+		}
+	}
+	if schema.If != nil && (schema.Then != nil || schema.Else != nil) {
+		checks += conditionalChecks(location, schema)
+	}
+	if schema.UnevaluatedProperties != nil {
+		checks += unevaluatedPropertiesChecks(location, schema)
+	}
+	return checks
+}
+
+// Counts the items that are valid against the contains schema. At least one
+// of them must be unless minContains says otherwise.
+func containsChecks(location string, schema *base.Schema) string {
+	minContains := int64(1)
+	if schema.MinContains != nil {
+		minContains = *schema.MinContains
+	}
+	checks := fmt.Sprintf(`
+	if items, ok := v.Array(); ok {
+		contains := 0
+		for _, item := range items {
+			if %s(item) {
+				contains++
+			}
+		}
+		if contains < %d {
+			errs = append(errs, NewMinContainsError(contains, %d))
+		}`,
+		predicate(location+"/contains", schema.Contains, nil), minContains, minContains,
+	)
+	if schema.MaxContains != nil {
+		checks += fmt.Sprintf(`
+		if contains > %d {
+			errs = append(errs, NewMaxContainsError(contains, %d))
+		}`,
+			*schema.MaxContains, *schema.MaxContains,
+		)
+	}
+	return checks + "\n\t}"
+}
+
+func conditionalChecks(location string, schema *base.Schema) string {
+	condition := predicate(location+"/if", schema.If, nil)
+	if schema.Then == nil {
+		return fmt.Sprintf(`
+	if !%s(v) && !%s(v) {
+		errs = append(errs, NewElseError())
+	}`,
+			condition, predicate(location+"/else", schema.Else, nil),
+		)
+	}
+	checks := fmt.Sprintf(`
+	if %s(v) {
+		if !%s(v) {
+			errs = append(errs, NewThenError())
+		}
+	}`,
+		condition, predicate(location+"/then", schema.Then, nil),
+	)
+	if schema.Else != nil {
+		checks += fmt.Sprintf(` else if !%s(v) {
+		errs = append(errs, NewElseError())
+	}`,
+			predicate(location+"/else", schema.Else, nil),
+		)
+	}
+	return checks
+}
+
+// Checks the properties that no other keyword evaluates. The properties of the
+// subschemas count as evaluated even when the subschema does not apply, for
+// example the properties of then when the if schema is not valid.
+func unevaluatedPropertiesChecks(location string, schema *base.Schema) string {
+	unevaluated := schema.UnevaluatedProperties
+	if schema.AdditionalProperties != nil || unevaluated.IsB() && unevaluated.B {
+		return ""
+	}
+	subschemas := []*base.SchemaProxy{schema.If, schema.Then, schema.Else}
+	subschemas = append(subschemas, schema.AllOf...)
+	subschemas = append(subschemas, schema.AnyOf...)
+	subschemas = append(subschemas, schema.OneOf...)
+	if schema.DependentSchemas != nil {
+		for subschema := range schema.DependentSchemas.ValuesFromOldest() {
+			subschemas = append(subschemas, subschema)
+		}
+	}
+	schemas := []*base.Schema{schema}
+	for _, subschema := range subschemas {
+		if subschema != nil && subschema.Schema() != nil {
+			schemas = append(schemas, subschema.Schema())
+		}
+	}
+	var evaluated []string
+	for _, schema := range schemas {
+		if schema.Properties == nil {
+			continue
+		}
+		for key := range schema.Properties.KeysFromOldest() {
+			if key := strconv.Quote(key); !slices.Contains(evaluated, key) {
+				evaluated = append(evaluated, key)
+			}
+		}
+	}
+	if unevaluated.IsA() {
+		return fmt.Sprintf(`
+	for _, key := range v.Keys() {
+		if p, _ := v.Property(key); !slices.Contains([]string{%s}, key) && !%s(p) {
+			errs = append(errs, NewUnevaluatedPropertyError(key))
+		}
+	}`,
+			strings.Join(evaluated, ", "),
+			predicate(location+"/unevaluatedProperties", unevaluated.A, nil),
+		)
+	}
+	return fmt.Sprintf(`
+	for _, key := range v.Keys() {
+		if !slices.Contains([]string{%s}, key) {
+			errs = append(errs, NewUnevaluatedPropertyError(key))
+		}
+	}`,
+		strings.Join(evaluated, ", "),
+	)
+}
+
+// A function literal that reports whether a JSON value is valid against a
+// subschema used as a condition, like the one of if or contains. Only the
+// keywords that such subschemas commonly have are supported.
+func predicate(location string, proxy *base.SchemaProxy, path []any) string {
+	// Boolean schemas, like false, accept every value or none.
+	if node := proxy.GetValueNode(); node != nil && node.Tag == "!!bool" {
+		valid, err := strconv.ParseBool(node.Value)
+		if err != nil {
+			failf(location, "%v", err)
+		}
+		return fmt.Sprintf("func(v RawValue) bool {\n\treturn %t\n}", valid)
+	}
+	schema := proxy.Schema()
+	if schema == nil {
+		failf(location, "cannot resolve reference %s: %v",
+			proxy.GetReference(), proxy.GetBuildError(),
+		)
+	}
+	if slices.Contains(path, schemaKey(schema)) {
+		failf(location, "recursive schemas are not supported as conditions")
+	}
+	path = append(slices.Clone(path), schemaKey(schema))
+	if keyword := unsupportedConditionKeyword(schema); keyword != "" {
+		failf(location, "keyword %s is not supported in conditions", keyword)
+	}
+
+	var body string
+	fail := "{\n\t\treturn false\n\t}"
+	if len(schema.Type) > 0 {
+		var types []string
+		for _, schemaType := range schema.Type {
+			types = append(types, strconv.Quote(schemaType))
+		}
+		body += fmt.Sprintf("\n\tif !v.Is(%s) %s", strings.Join(types, ", "), fail)
+	}
+	if schema.Const != nil {
+		body += fmt.Sprintf("\n\tif !v.Equal(%s) %s",
+			jsonLiteral(location+"/const", schema.Const), fail,
+		)
+	}
+	if len(schema.Enum) > 0 {
+		var conditions []string
+		for i, value := range schema.Enum {
+			conditions = append(conditions, fmt.Sprintf("!v.Equal(%s)",
+				jsonLiteral(location+"/enum/"+strconv.Itoa(i), value),
+			))
+		}
+		body += fmt.Sprintf("\n\tif %s %s", strings.Join(conditions, " && "), fail)
+	}
+	if len(schema.Required) > 0 {
+		var keys []string
+		for _, key := range schema.Required {
+			keys = append(keys, strconv.Quote(key))
+		}
+		body += fmt.Sprintf("\n\tif v.Type() == \"object\" && !v.Has(%s) %s",
+			strings.Join(keys, ", "), fail,
+		)
+	}
+	if schema.Properties != nil {
+		for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+			body += fmt.Sprintf("\n\tif p, ok := v.Property(%q); ok && !%s(p) %s",
+				pair.Key(),
+				predicate(location+"/properties/"+EscapeJSONPointer(pair.Key()), pair.Value(), path),
+				fail,
+			)
+		}
+	}
+	if schema.Items != nil && schema.Items.IsA() {
+		body += fmt.Sprintf(`
+	if items, ok := v.Array(); ok {
+		for _, item := range items {
+			if !%s(item) {
+				return false
+			}
+		}
+	}`,
+			predicate(location+"/items", schema.Items.A, path),
+		)
+	}
+	if schema.MinItems != nil {
+		body += fmt.Sprintf("\n\tif items, ok := v.Array(); ok && len(items) < %d %s",
+			*schema.MinItems, fail,
+		)
+	}
+	if schema.MaxItems != nil {
+		body += fmt.Sprintf("\n\tif items, ok := v.Array(); ok && len(items) > %d %s",
+			*schema.MaxItems, fail,
+		)
+	}
+	if schema.MinLength != nil {
+		body += fmt.Sprintf("\n\tif s, ok := v.String(); ok && len([]rune(s)) < %d %s",
+			*schema.MinLength, fail,
+		)
+	}
+	if schema.MaxLength != nil {
+		body += fmt.Sprintf("\n\tif s, ok := v.String(); ok && len([]rune(s)) > %d %s",
+			*schema.MaxLength, fail,
+		)
+	}
+	if schema.Minimum != nil {
+		body += fmt.Sprintf("\n\tif n, ok := v.Number(); ok && n < %s %s",
+			strconv.FormatFloat(*schema.Minimum, 'g', -1, 64), fail,
+		)
+	}
+	if schema.Maximum != nil {
+		body += fmt.Sprintf("\n\tif n, ok := v.Number(); ok && n > %s %s",
+			strconv.FormatFloat(*schema.Maximum, 'g', -1, 64), fail,
+		)
+	}
+	return "func(v RawValue) bool {" + body + "\n\treturn true\n}"
+}
+
+// The first keyword of a schema used as a condition that is not supported.
+func unsupportedConditionKeyword(schema *base.Schema) string {
+	keywords := []struct {
+		name string
+		set  bool
+	}{
+		{"allOf", len(schema.AllOf) > 0},
+		{"anyOf", len(schema.AnyOf) > 0},
+		{"oneOf", len(schema.OneOf) > 0},
+		{"not", schema.Not != nil},
+		{"if", schema.If != nil},
+		{"contains", schema.Contains != nil},
+		{"minContains", schema.MinContains != nil},
+		{"maxContains", schema.MaxContains != nil},
+		{"prefixItems", len(schema.PrefixItems) > 0},
+		{"unevaluatedItems", schema.UnevaluatedItems != nil},
+		{"dependentSchemas", schema.DependentSchemas != nil},
+		{"dependentRequired", SchemaKeyword(schema, "dependentRequired") != nil},
+		{"additionalProperties", schema.AdditionalProperties != nil},
+		{"unevaluatedProperties", schema.UnevaluatedProperties != nil},
+		{"patternProperties", schema.PatternProperties != nil && schema.PatternProperties.Len() > 0},
+		{"propertyNames", schema.PropertyNames != nil},
+		{"$dynamicRef", SchemaKeyword(schema, "$dynamicRef") != nil},
+		{"pattern", schema.Pattern != ""},
+		{"multipleOf", schema.MultipleOf != nil},
+		{"exclusiveMinimum", schema.ExclusiveMinimum != nil},
+		{"exclusiveMaximum", schema.ExclusiveMaximum != nil},
+		{"uniqueItems", schema.UniqueItems != nil && *schema.UniqueItems},
+		{"minProperties", schema.MinProperties != nil},
+		{"maxProperties", schema.MaxProperties != nil},
+	}
+	for _, keyword := range keywords {
+		if keyword.set {
+			return keyword.name
+		}
+	}
+	return ""
+}
+
+// A Go string literal with the JSON encoding of a value in the specification.
+func jsonLiteral(location string, node *yaml.Node) string {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		failf(location, "%v", err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		failf(location, "%v", err)
+	}
+	return strconv.Quote(string(data))
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueChecks(t *testing.T) {
+	testCases := map[string]struct {
+		schema   string
+		expected []string
+	}{
+		"const": {
+			schema: `{"const": "cat"}`,
+			expected: []string{
+				`if !v.Equal("\"cat\"") {`,
+				`errs = append(errs, NewConstError(data, "\"cat\""))`,
+			},
+		},
+		"contains": {
+			schema: `{"type": "array", "items": {"type": "integer"},
+				"contains": {"minimum": 10}, "minContains": 2, "maxContains": 3}`,
+			expected: []string{
+				"if n, ok := v.Number(); ok && n < 10 {",
+				"if contains < 2 {",
+				"errs = append(errs, NewMaxContainsError(contains, 3))",
+			},
+		},
+		"dependentRequired": {
+			schema: `{"type": "object", "dependentRequired": {"owner": ["email", "phone"]}}`,
+			expected: []string{
+				`if v.Has("owner") && !v.Has("email") {`,
+				`errs = append(errs, NewDependentRequiredError("owner", "phone"))`,
+			},
+		},
+		"dependentSchemas": {
+			schema: `{"type": "object", "dependentSchemas": {"vet": {"required": ["owner"]}}}`,
+			expected: []string{
+				`if v.Type() == "object" && !v.Has("owner") {`,
+				`errs = append(errs, NewDependentSchemasError("vet"))`,
+			},
+		},
+		"if then else": {
+			schema: `{"type": "object",
+				"if": {"properties": {"kind": {"const": "cat"}}},
+				"then": {"required": ["meows"]},
+				"else": {"properties": {"meows": false}}}`,
+			expected: []string{
+				`if p, ok := v.Property("kind"); ok && !func(v RawValue) bool {`,
+				"errs = append(errs, NewThenError())",
+				"} else if !func(v RawValue) bool {",
+				"return false\n}(p) {",
+			},
+		},
+		"unevaluatedProperties": {
+			schema: `{"type": "object", "properties": {"a": {"type": "string"}},
+				"then": {"properties": {"b": {"type": "string"}}},
+				"unevaluatedProperties": false}`,
+			expected: []string{
+				`if !slices.Contains([]string{"a", "b"}, key) {`,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec, err := loadOpenAPIDocument([]byte(`{
+				"openapi": "3.1.0",
+				"info": {"title": "Checks", "version": "1.0.0"},
+				"paths": {},
+				"components": {"schemas": {"checked": `+tc.schema+`}}
+			}`), ".")
+			require.NoError(t, err)
+
+			modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+			require.Empty(t, diagnostics)
+			require.NotEmpty(t, modelTypes)
+			methods := modelTypes[0].Methods()
+			assert.Contains(t, methods, "func (m *Checked) UnmarshalJSON(data []byte) error {")
+			for _, expected := range tc.expected {
+				assert.Contains(t, methods, expected)
+			}
+		})
+	}
+}
+
+func TestUnsupportedKeywords(t *testing.T) {
+	testCases := map[string]struct {
+		schema   string
+		expected string
+	}{
+		"propertyNames": {
+			schema:   `{"type": "object", "propertyNames": {"maxLength": 3}}`,
+			expected: "#/components/schemas/checked: keyword propertyNames is not supported",
+		},
+		"allOf": {
+			schema:   `{"allOf": [{"type": "object"}, {"required": ["a"]}]}`,
+			expected: "#/components/schemas/checked: keyword allOf is not supported",
+		},
+		"oneOf": {
+			schema:   `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`,
+			expected: "#/components/schemas/checked: keyword oneOf is not supported",
+		},
+		"anyOf": {
+			schema:   `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			expected: "#/components/schemas/checked: keyword anyOf is not supported",
+		},
+		"not": {
+			schema: `{"type": "object", "properties": {"a": {
+				"type": "string", "not": {"const": "b"}}}}`,
+			expected: "#/components/schemas/checked/properties/a: keyword not is not supported",
+		},
+		"closed object": {
+			schema:   `{"type": "object", "additionalProperties": false}`,
+			expected: "#/components/schemas/checked: keyword additionalProperties is not supported",
+		},
+		"map": {
+			schema:   `{"type": "object", "additionalProperties": {"type": "integer"}}`,
+			expected: "#/components/schemas/checked: keyword additionalProperties is not supported",
+		},
+		// The structs of objects ignore unknown properties already.
+		"open object": {
+			schema: `{"type": "object", "additionalProperties": true}`,
+		},
+		"empty additional properties": {
+			schema: `{"type": "object", "additionalProperties": {}}`,
+		},
+		"nested condition": {
+			schema: `{"type": "object", "properties": {"a": {
+				"type": "array", "items": {"type": "string"},
+				"contains": {"pattern": "^a"}}}}`,
+			expected: "#/components/schemas/checked/properties/a/contains: " +
+				"keyword pattern is not supported in conditions",
+		},
+		"patternProperties in a condition": {
+			schema: `{"type": "object", "if": {"patternProperties": {"^x-": {"type": "string"}}}, "then": {"required": ["a"]}}`,
+			expected: "#/components/schemas/checked/if: " +
+				"keyword patternProperties is not supported in conditions",
+		},
+		"propertyNames in a condition": {
+			schema: `{"type": "object", "if": {"propertyNames": {"maxLength": 3}}, "then": {"required": ["a"]}}`,
+			expected: "#/components/schemas/checked/if: " +
+				"keyword propertyNames is not supported in conditions",
+		},
+		"unevaluatedItems in a condition": {
+			schema: `{"type": "object", "if": {"unevaluatedItems": {"type": "string"}}, "then": {"required": ["a"]}}`,
+			expected: "#/components/schemas/checked/if: " +
+				"keyword unevaluatedItems is not supported in conditions",
+		},
+		"$dynamicRef in a condition": {
+			schema: `{"type": "object", "if": {"$dynamicRef": "#meta"}, "then": {"required": ["a"]}}`,
+			expected: "#/components/schemas/checked/if: " +
+				"keyword $dynamicRef is not supported in conditions",
+		},
+		"minContains in a condition": {
+			schema: `{"type": "object", "if": {"minContains": 2}, "then": {"required": ["a"]}}`,
+			expected: "#/components/schemas/checked/if: " +
+				"keyword minContains is not supported in conditions",
+		},
+		"maxContains in a condition": {
+			schema: `{"type": "object", "if": {"maxContains": 1}, "then": {"required": ["a"]}}`,
+			expected: "#/components/schemas/checked/if: " +
+				"keyword maxContains is not supported in conditions",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec, err := loadOpenAPIDocument([]byte(`{
+				"openapi": "3.1.0",
+				"info": {"title": "Checks", "version": "1.0.0"},
+				"paths": {},
+				"components": {"schemas": {"checked": `+tc.schema+`}}
+			}`), ".")
+			require.NoError(t, err)
+
+			_, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+			if tc.expected == "" {
+				assert.Empty(t, diagnostics)
+				return
+			}
+			assert.EqualError(t, diagnostics, tc.expected)
+		})
+	}
+}
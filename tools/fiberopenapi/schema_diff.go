@@ -0,0 +1,347 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// The formats that allow more values than others of the same type.
+var widerFormats = map[string]string{
+	"int32": "int64",
+	"float": "double",
+}
+
+// Compares the schemas of the messages in a direction. The changes of a
+// referenced schema are reported where it is declared, like
+// #/components/schemas/Pet, once for all the messages that use it.
+func (d *specDiff) compareSchemas(pointer string, old, new *base.SchemaProxy, direction messageDirection) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		d.report(pointer, narrowingChange, direction, "schema was added")
+		return
+	case new == nil:
+		d.report(pointer, wideningChange, direction, "schema was removed")
+		return
+	}
+	if old.IsReference() || new.IsReference() {
+		key := old.GetReference() + "\n" + new.GetReference() + "\n" + direction.String()
+		if d.compared[key] {
+			return
+		}
+		d.compared[key] = true
+		if strings.HasPrefix(new.GetReference(), "#/") {
+			pointer = new.GetReference()
+		}
+	}
+	oldSchema, newSchema := old.Schema(), new.Schema()
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+	if !d.compareTypes(pointer, oldSchema, newSchema, direction) {
+		// The rest of the keywords of incompatible types are not comparable.
+		return
+	}
+	d.compareFormats(pointer, oldSchema.Format, newSchema.Format, direction)
+	d.compareValues(pointer, "enum", oldSchema.Enum, newSchema.Enum, direction)
+	d.compareValues(pointer, "const", nodes(oldSchema.Const), nodes(newSchema.Const), direction)
+	d.compareBound(pointer, "maxLength", float(oldSchema.MaxLength), float(newSchema.MaxLength), true, direction)
+	d.compareBound(pointer, "minLength", float(oldSchema.MinLength), float(newSchema.MinLength), false, direction)
+	d.compareBound(pointer, "maximum", oldSchema.Maximum, newSchema.Maximum, true, direction)
+	d.compareBound(pointer, "minimum", oldSchema.Minimum, newSchema.Minimum, false, direction)
+	d.compareBound(pointer, "exclusiveMaximum", exclusiveBound(oldSchema.ExclusiveMaximum), exclusiveBound(newSchema.ExclusiveMaximum), true, direction)
+	d.compareBound(pointer, "exclusiveMinimum", exclusiveBound(oldSchema.ExclusiveMinimum), exclusiveBound(newSchema.ExclusiveMinimum), false, direction)
+	d.compareFlag(pointer, "exclusiveMaximum", exclusiveFlag(oldSchema.ExclusiveMaximum), exclusiveFlag(newSchema.ExclusiveMaximum), direction)
+	d.compareFlag(pointer, "exclusiveMinimum", exclusiveFlag(oldSchema.ExclusiveMinimum), exclusiveFlag(newSchema.ExclusiveMinimum), direction)
+	d.compareBound(pointer, "maxItems", float(oldSchema.MaxItems), float(newSchema.MaxItems), true, direction)
+	d.compareBound(pointer, "minItems", float(oldSchema.MinItems), float(newSchema.MinItems), false, direction)
+	d.compareBound(pointer, "maxProperties", float(oldSchema.MaxProperties), float(newSchema.MaxProperties), true, direction)
+	d.compareBound(pointer, "minProperties", float(oldSchema.MinProperties), float(newSchema.MinProperties), false, direction)
+	d.compareFlag(pointer, "uniqueItems", oldSchema.UniqueItems != nil && *oldSchema.UniqueItems, newSchema.UniqueItems != nil && *newSchema.UniqueItems, direction)
+	d.compareMultipleOf(pointer, oldSchema.MultipleOf, newSchema.MultipleOf, direction)
+	d.comparePatterns(pointer, oldSchema.Pattern, newSchema.Pattern, direction)
+	d.compareProperties(pointer, oldSchema, newSchema, direction)
+	if oldSchema.Items != nil && newSchema.Items != nil && oldSchema.Items.IsA() && newSchema.Items.IsA() {
+		d.compareSchemas(pointer+"/items", oldSchema.Items.A, newSchema.Items.A, direction)
+	}
+	d.compareCompositions(pointer, "allOf", oldSchema.AllOf, newSchema.AllOf, direction)
+	d.compareCompositions(pointer, "oneOf", oldSchema.OneOf, newSchema.OneOf, direction)
+	d.compareCompositions(pointer, "anyOf", oldSchema.AnyOf, newSchema.AnyOf, direction)
+}
+
+// Compares the types of two schemas, and returns whether they are compatible.
+// An integer is a number, and a schema without type allows any.
+func (d *specDiff) compareTypes(pointer string, old, new *base.Schema, direction messageDirection) bool {
+	oldTypes, newTypes := schemaTypes(old), schemaTypes(new)
+	switch {
+	case len(oldTypes) == 0 && len(newTypes) == 0:
+		return true
+	case len(oldTypes) == 0:
+		d.report(pointer, narrowingChange, direction, "type %s was added", strings.Join(newTypes, " or "))
+		return true
+	case len(newTypes) == 0:
+		d.report(pointer, wideningChange, direction, "type %s was removed", strings.Join(oldTypes, " or "))
+		return true
+	}
+	removed := slices.DeleteFunc(slices.Clone(oldTypes), func(t string) bool { return allowsType(newTypes, t) })
+	added := slices.DeleteFunc(slices.Clone(newTypes), func(t string) bool { return allowsType(oldTypes, t) })
+	switch {
+	case len(removed) > 0 && len(added) > 0:
+		d.report(pointer, incompatibleChange, direction, "type changed from %s to %s",
+			strings.Join(oldTypes, " or "), strings.Join(newTypes, " or "))
+		return false
+	case len(removed) > 0:
+		d.report(pointer, narrowingChange, direction, "type %s is no longer allowed", strings.Join(removed, " or "))
+	case len(added) > 0:
+		d.report(pointer, wideningChange, direction, "type %s is now allowed", strings.Join(added, " or "))
+	}
+	return true
+}
+
+// The types of a schema, with null when it is nullable.
+func schemaTypes(schema *base.Schema) []string {
+	types := slices.Clone(schema.Type)
+	if schema.Nullable != nil && *schema.Nullable && !slices.Contains(types, "null") {
+		types = append(types, "null")
+	}
+	return types
+}
+
+func allowsType(types []string, t string) bool {
+	return slices.Contains(types, t) || t == "integer" && slices.Contains(types, "number")
+}
+
+func (d *specDiff) compareFormats(pointer, old, new string, direction messageDirection) {
+	switch {
+	case old == new:
+	case old == "":
+		d.report(pointer, narrowingChange, direction, "format %s was added", new)
+	case new == "":
+		d.report(pointer, wideningChange, direction, "format %s was removed", old)
+	case widerFormats[old] == new:
+		d.report(pointer, wideningChange, direction, "format changed from %s to %s", old, new)
+	case widerFormats[new] == old:
+		d.report(pointer, narrowingChange, direction, "format changed from %s to %s", old, new)
+	default:
+		d.report(pointer, incompatibleChange, direction, "format changed from %s to %s", old, new)
+	}
+}
+
+// Compares the allowed values of a keyword like enum, where no values allow
+// any.
+func (d *specDiff) compareValues(pointer, keyword string, old, new []*yaml.Node, direction messageDirection) {
+	oldValues, newValues := nodeValues(old), nodeValues(new)
+	switch {
+	case len(oldValues) == 0 && len(newValues) == 0:
+		return
+	case len(oldValues) == 0:
+		d.report(pointer, narrowingChange, direction, "%s %s was added", keyword, strings.Join(newValues, ", "))
+		return
+	case len(newValues) == 0:
+		d.report(pointer, wideningChange, direction, "%s %s was removed", keyword, strings.Join(oldValues, ", "))
+		return
+	}
+	for _, value := range oldValues {
+		if !slices.Contains(newValues, value) {
+			d.report(pointer, narrowingChange, direction, "%s value %s was removed", keyword, value)
+		}
+	}
+	for _, value := range newValues {
+		if !slices.Contains(oldValues, value) {
+			d.report(pointer, wideningChange, direction, "%s value %s was added", keyword, value)
+		}
+	}
+}
+
+// The values of nodes, as written in the specification.
+func nodeValues(nodes []*yaml.Node) []string {
+	var values []string
+	for _, node := range nodes {
+		if node.Kind == yaml.ScalarNode {
+			values = append(values, node.Value)
+			continue
+		}
+		data, err := yaml.Marshal(node)
+		if err == nil {
+			values = append(values, strings.TrimSpace(string(data)))
+		}
+	}
+	return values
+}
+
+func nodes(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	return []*yaml.Node{node}
+}
+
+// Compares a bound like maxLength, which is an upper bound, or minLength.
+func (d *specDiff) compareBound(pointer, keyword string, old, new *float64, upper bool, direction messageDirection) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.report(pointer, narrowingChange, direction, "%s %s was added", keyword, formatNumber(*new))
+	case new == nil:
+		d.report(pointer, wideningChange, direction, "%s %s was removed", keyword, formatNumber(*old))
+	case *old == *new:
+	case (*new < *old) == upper:
+		d.report(pointer, narrowingChange, direction, "%s changed from %s to %s", keyword, formatNumber(*old), formatNumber(*new))
+	default:
+		d.report(pointer, wideningChange, direction, "%s changed from %s to %s", keyword, formatNumber(*old), formatNumber(*new))
+	}
+}
+
+// Compares a keyword that constrains the values when it is true, like
+// uniqueItems.
+func (d *specDiff) compareFlag(pointer, keyword string, old, new bool, direction messageDirection) {
+	switch {
+	case !old && new:
+		d.report(pointer, narrowingChange, direction, "%s was added", keyword)
+	case old && !new:
+		d.report(pointer, wideningChange, direction, "%s was removed", keyword)
+	}
+}
+
+func (d *specDiff) compareMultipleOf(pointer string, old, new *float64, direction messageDirection) {
+	isMultiple := func(a, b float64) bool {
+		return b != 0 && math.Mod(a, b) == 0
+	}
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.report(pointer, narrowingChange, direction, "multipleOf %s was added", formatNumber(*new))
+	case new == nil:
+		d.report(pointer, wideningChange, direction, "multipleOf %s was removed", formatNumber(*old))
+	case *old == *new:
+	case isMultiple(*new, *old):
+		d.report(pointer, narrowingChange, direction, "multipleOf changed from %s to %s", formatNumber(*old), formatNumber(*new))
+	case isMultiple(*old, *new):
+		d.report(pointer, wideningChange, direction, "multipleOf changed from %s to %s", formatNumber(*old), formatNumber(*new))
+	default:
+		d.report(pointer, incompatibleChange, direction, "multipleOf changed from %s to %s", formatNumber(*old), formatNumber(*new))
+	}
+}
+
+func (d *specDiff) comparePatterns(pointer, old, new string, direction messageDirection) {
+	switch {
+	case old == new:
+	case old == "":
+		d.report(pointer, narrowingChange, direction, "pattern %s was added", new)
+	case new == "":
+		d.report(pointer, wideningChange, direction, "pattern %s was removed", old)
+	default:
+		// Whether a pattern matches more or less strings than another one is
+		// not known.
+		d.report(pointer, incompatibleChange, direction, "pattern changed from %s to %s", old, new)
+	}
+}
+
+// Compares the properties of objects, and which of them are required.
+func (d *specDiff) compareProperties(pointer string, old, new *base.Schema, direction messageDirection) {
+	for _, name := range new.Required {
+		if !slices.Contains(old.Required, name) {
+			d.report(pointer, narrowingChange, direction, "property %s is now required", name)
+		}
+	}
+	for _, name := range old.Required {
+		if !slices.Contains(new.Required, name) {
+			d.report(pointer, wideningChange, direction, "property %s is no longer required", name)
+		}
+	}
+	oldClosed := old.AdditionalProperties != nil && old.AdditionalProperties.IsB() && !old.AdditionalProperties.B
+	newClosed := new.AdditionalProperties != nil && new.AdditionalProperties.IsB() && !new.AdditionalProperties.B
+	for pair := old.Properties.First(); pair != nil; pair = pair.Next() {
+		name := pair.Key()
+		propertyPointer := pointer + "/properties/" + EscapeJSONPointer(name)
+		if new.Properties != nil {
+			if newProperty, ok := new.Properties.Get(name); ok {
+				d.compareSchemas(propertyPointer, pair.Value(), newProperty, direction)
+				continue
+			}
+		}
+		// Objects without additional properties no longer allow the property.
+		// Otherwise, clients no longer receive it, and servers ignore it.
+		effect := compatibleChange
+		switch {
+		case newClosed:
+			effect = narrowingChange
+		case direction == responseMessage:
+			effect = wideningChange
+		}
+		d.report(propertyPointer, effect, direction, "property %s was removed", name)
+	}
+	for pair := new.Properties.First(); pair != nil; pair = pair.Next() {
+		if old.Properties != nil {
+			if _, ok := old.Properties.Get(pair.Key()); ok {
+				continue
+			}
+		}
+		effect := compatibleChange
+		if oldClosed {
+			effect = wideningChange
+		}
+		d.report(pointer+"/properties/"+EscapeJSONPointer(pair.Key()), effect, direction, "property %s was added", pair.Key())
+	}
+	switch {
+	case !oldClosed && newClosed:
+		d.report(pointer, narrowingChange, direction, "additional properties are no longer allowed")
+	case oldClosed && !newClosed:
+		d.report(pointer, wideningChange, direction, "additional properties are now allowed")
+	case old.AdditionalProperties != nil && new.AdditionalProperties != nil &&
+		old.AdditionalProperties.IsA() && new.AdditionalProperties.IsA():
+		d.compareSchemas(pointer+"/additionalProperties", old.AdditionalProperties.A, new.AdditionalProperties.A, direction)
+	}
+}
+
+// Compares the subschemas of allOf, oneOf or anyOf by position. More schemas
+// in allOf allow less values, and more in oneOf or anyOf allow more.
+func (d *specDiff) compareCompositions(pointer, keyword string, old, new []*base.SchemaProxy, direction messageDirection) {
+	switch {
+	case len(old) == len(new):
+		for i := range old {
+			d.compareSchemas(pointer+"/"+keyword+"/"+strconv.Itoa(i), old[i], new[i], direction)
+		}
+	case len(old) == 0:
+		d.report(pointer, narrowingChange, direction, "%s was added", keyword)
+	case len(new) == 0:
+		d.report(pointer, wideningChange, direction, "%s was removed", keyword)
+	case (len(new) > len(old)) == (keyword == "allOf"):
+		d.report(pointer, narrowingChange, direction, "%s changed from %d to %d schemas", keyword, len(old), len(new))
+	default:
+		d.report(pointer, wideningChange, direction, "%s changed from %d to %d schemas", keyword, len(old), len(new))
+	}
+}
+
+func float(value *int64) *float64 {
+	if value == nil {
+		return nil
+	}
+	result := float64(*value)
+	return &result
+}
+
+// The bound of exclusiveMaximum or exclusiveMinimum in OpenAPI 3.1, where they
+// are numbers.
+func exclusiveBound(value *base.DynamicValue[bool, float64]) *float64 {
+	if value == nil || !value.IsB() {
+		return nil
+	}
+	return &value.B
+}
+
+// Whether exclusiveMaximum or exclusiveMinimum is set in OpenAPI 3.0, where
+// they are flags of maximum and minimum.
+func exclusiveFlag(value *base.DynamicValue[bool, float64]) bool {
+	return value != nil && value.IsA() && value.A
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+// The value of a keyword of a schema as written in the specification, for the
+// keywords that libopenapi does not model, like dependentRequired.
+func SchemaKeyword(schema *base.Schema, keyword string) *yaml.Node {
+	low := schema.GoLow()
+	if low == nil {
+		return nil
+	}
+	return mappingValue(low.RootNode, keyword)
+}
+
+// The properties that are required when each property is present, as set with
+// the dependentRequired keyword.
+func DependentRequired(schema *base.Schema) (*orderedmap.Map[string, []string], error) {
+	node := SchemaKeyword(schema, "dependentRequired")
+	if node == nil {
+		return nil, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("dependentRequired must be an object")
+	}
+	dependentRequired := orderedmap.New[string, []string]()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var required []string
+		if err := node.Content[i+1].Decode(&required); err != nil {
+			return nil, fmt.Errorf("dependentRequired %s must be an array of strings: %w",
+				node.Content[i].Value, err,
+			)
+		}
+		dependentRequired.Set(node.Content[i].Value, required)
+	}
+	return dependentRequired, nil
+}
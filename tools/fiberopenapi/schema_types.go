@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// The types of the values that a schema accepts. When the type keyword is
+// absent, the type is inferred from the keywords that only apply to some
+// types: properties for objects, items for arrays and the values of enum or
+// const for scalars. Free-form schemas, like {}, have no types.
+func SchemaTypes(schema *base.Schema) []string {
+	if len(schema.Type) > 0 {
+		return schema.Type
+	}
+	switch {
+	case schema.Properties != nil && schema.Properties.Len() > 0,
+		schema.AdditionalProperties != nil,
+		len(schema.Required) > 0:
+		return []string{"object"}
+	case schema.Items != nil, len(schema.PrefixItems) > 0:
+		return []string{"array"}
+	case len(schema.Enum) > 0:
+		return valueTypes(schema.Enum)
+	case schema.Const != nil:
+		return valueTypes([]*yaml.Node{schema.Const})
+	}
+	return nil
+}
+
+// The types of some YAML values, in order of appearance. Numbers are
+// integers only when all of them are integers.
+func valueTypes(values []*yaml.Node) []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, value := range values {
+		var valueType string
+		switch value.Tag {
+		case "!!str":
+			valueType = "string"
+		case "!!int":
+			valueType = "integer"
+		case "!!float":
+			valueType = "number"
+		case "!!bool":
+			valueType = "boolean"
+		case "!!null":
+			valueType = "null"
+		case "!!map":
+			valueType = "object"
+		case "!!seq":
+			valueType = "array"
+		}
+		if valueType != "" && !seen[valueType] {
+			seen[valueType] = true
+			types = append(types, valueType)
+		}
+	}
+	if seen["integer"] && seen["number"] {
+		for i, valueType := range types {
+			if valueType == "integer" {
+				return append(types[:i], types[i+1:]...)
+			}
+		}
+	}
+	return types
+}
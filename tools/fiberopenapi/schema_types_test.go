@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchemaTypes(t *testing.T) {
+	values := func(values ...string) []*yaml.Node {
+		var nodes []*yaml.Node
+		for _, value := range values {
+			var node yaml.Node
+			if err := yaml.Unmarshal([]byte(value), &node); err != nil {
+				t.Fatal(err)
+			}
+			nodes = append(nodes, node.Content[0])
+		}
+		return nodes
+	}
+	testCases := map[string]struct {
+		schema   *base.Schema
+		expected []string
+	}{
+		"explicit type": {
+			schema:   &base.Schema{Type: []string{"string"}},
+			expected: []string{"string"},
+		},
+		"multiple types": {
+			schema:   &base.Schema{Type: []string{"string", "null"}},
+			expected: []string{"string", "null"},
+		},
+		"free-form": {
+			schema:   &base.Schema{},
+			expected: nil,
+		},
+		"properties": {
+			schema: func() *base.Schema {
+				schema := &base.Schema{}
+				schema.Properties = orderedmap.New[string, *base.SchemaProxy]()
+				schema.Properties.Set("name", base.CreateSchemaProxy(&base.Schema{}))
+				return schema
+			}(),
+			expected: []string{"object"},
+		},
+		"items": {
+			schema: &base.Schema{Items: &base.DynamicValue[*base.SchemaProxy, bool]{
+				A: base.CreateSchemaProxy(&base.Schema{}),
+			}},
+			expected: []string{"array"},
+		},
+		"string enum": {
+			schema:   &base.Schema{Enum: values(`"X"`, `"O"`)},
+			expected: []string{"string"},
+		},
+		"integer and number enum": {
+			schema:   &base.Schema{Enum: values("1", "1.5", "null")},
+			expected: []string{"number", "null"},
+		},
+		"const": {
+			schema:   &base.Schema{Const: values("true")[0]},
+			expected: []string{"boolean"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SchemaTypes(tc.schema))
+		})
+	}
+}
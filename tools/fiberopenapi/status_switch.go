@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// How the responses of an operation are told apart by their status code:
+// status codes are matched exactly first, then by range like 4XX, and then the
+// default response is used.
+type StatusSwitch struct {
+	Cases   []StatusCase
+	Default *Response
+}
+
+// The response of the status codes that match a condition, like
+// status == 200 or status/100 == 4, on a status variable.
+type StatusCase struct {
+	Condition string
+	Response
+}
+
+// Whether any of the responses has writeOnly properties.
+func (s StatusSwitch) WriteOnly() bool {
+	if s.Default != nil && s.Default.WriteOnly {
+		return true
+	}
+	for _, c := range s.Cases {
+		if c.WriteOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// How the responses of the operation are told apart by their status code.
+func (o Operation) StatusSwitch() StatusSwitch {
+	var exact, ranges []StatusCase
+	var result StatusSwitch
+	for _, response := range o.Responses {
+		switch {
+		case response.Code == "default":
+			result.Default = &response
+		case strings.HasSuffix(strings.ToUpper(response.Code), "XX"):
+			ranges = append(ranges, StatusCase{fmt.Sprintf("status/100 == %s", response.Code[:1]), response})
+		default:
+			exact = append(exact, StatusCase{fmt.Sprintf("status == %s", response.Code), response})
+		}
+	}
+	result.Cases = append(exact, ranges...)
+	return result
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusSwitch(t *testing.T) {
+	tests := map[string]struct {
+		responses []Response
+		expected  StatusSwitch
+	}{
+		"exact codes before ranges": {
+			responses: []Response{
+				{Code: "4XX", Type: "Error"},
+				{Code: "default", Type: "Problem"},
+				{Code: "200", Type: "Pet"},
+			},
+			expected: StatusSwitch{
+				Cases: []StatusCase{
+					{Condition: "status == 200", Response: Response{Code: "200", Type: "Pet"}},
+					{Condition: "status/100 == 4", Response: Response{Code: "4XX", Type: "Error"}},
+				},
+				Default: &Response{Code: "default", Type: "Problem"},
+			},
+		},
+		"only default": {
+			responses: []Response{{Code: "default", Type: "Problem"}},
+			expected:  StatusSwitch{Default: &Response{Code: "default", Type: "Problem"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Operation{Responses: test.responses}.StatusSwitch())
+		})
+	}
+}
+
+func TestStatusSwitchWriteOnly(t *testing.T) {
+	tests := map[string]struct {
+		responses []Response
+		expected  bool
+	}{
+		"case":    {responses: []Response{{Code: "200"}, {Code: "201", WriteOnly: true}}, expected: true},
+		"default": {responses: []Response{{Code: "200"}, {Code: "default", WriteOnly: true}}, expected: true},
+		"none":    {responses: []Response{{Code: "200"}, {Code: "default"}}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Operation{Responses: test.responses}.StatusSwitch().WriteOnly())
+		})
+	}
+}
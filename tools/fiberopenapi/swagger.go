@@ -0,0 +1,706 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The keywords of the type of Swagger 2.0 parameters and headers, which are in
+// their schema in OpenAPI 3.
+var swaggerTypeKeywords = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum",
+	"minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern",
+	"maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+var swaggerMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// The flows of OAuth2 security definitions of Swagger 2.0 with their names in
+// OpenAPI 3.
+var swaggerOAuthFlows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// The prefixes of the pointers of Swagger 2.0 with the ones of OpenAPI 3.
+var swaggerPointerPrefixes = [][2]string{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+	{"#/securityDefinitions/", "#/components/securitySchemes/"},
+}
+
+var (
+	swaggerContentPattern   = regexp.MustCompile(`/content/[^/]+`)
+	swaggerParameterPattern = regexp.MustCompile(`/(parameters|requestBody)(/.*)?$`)
+)
+
+// Converts a Swagger 2.0 specification to OpenAPI 3.0, the version that the
+// models of the generator are built from. Body and form data parameters become
+// request bodies, definitions become component schemas, and the media types
+// that operations consume and produce become the content of their request
+// bodies and responses. What does not convert is reported as warnings, at
+// pointers of the Swagger specification. Other specifications are returned as
+// they are.
+func ConvertSwagger(spec []byte) ([]byte, Diagnostics, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err != nil {
+		return nil, nil, fmt.Errorf("cannot parse specification: %w", err)
+	}
+	if !isSwagger(&root) {
+		return spec, nil, nil
+	}
+	document := root.Content[0]
+	if version := mappingValue(document, "swagger").Value; version != "2.0" {
+		return nil, nil, fmt.Errorf("unsupported Swagger version %s", version)
+	}
+	c := &swaggerConverter{
+		document: document,
+		consumes: stringValues(mappingValue(document, "consumes")),
+		produces: stringValues(mappingValue(document, "produces")),
+	}
+	root.Content[0] = c.convert()
+	rewriteSwaggerReferences(root.Content[0])
+	converted, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot encode converted specification: %w", err)
+	}
+	return converted, c.diagnostics, nil
+}
+
+func isSwagger(root *yaml.Node) bool {
+	return len(root.Content) > 0 && mappingValue(root.Content[0], "swagger") != nil
+}
+
+// The pointer of a Swagger 2.0 specification to what a pointer of its
+// conversion points to, or to its closest parent that converts to something
+// else, like the parameters of an operation for its request body.
+func swaggerPointer(pointer string) string {
+	for _, prefixes := range swaggerPointerPrefixes {
+		if strings.HasPrefix(pointer, prefixes[1]) {
+			pointer = prefixes[0] + strings.TrimPrefix(pointer, prefixes[1])
+		}
+	}
+	pointer = swaggerContentPattern.ReplaceAllString(pointer, "")
+	// The parameters without a body lose their position, and their types are
+	// not in a schema.
+	if strings.HasPrefix(pointer, "#/paths/") {
+		pointer = swaggerParameterPattern.ReplaceAllString(pointer, "/parameters")
+	}
+	return pointer
+}
+
+type swaggerConverter struct {
+	document *yaml.Node
+	// The media types of the specification, which operations can override.
+	consumes, produces []string
+	diagnostics        Diagnostics
+}
+
+// A body or form data parameter, which is part of a request body in
+// OpenAPI 3.
+type swaggerParameter struct {
+	pointer string
+	node    *yaml.Node
+}
+
+func (p swaggerParameter) in() string {
+	return scalarValue(mappingValue(p.node, "in"))
+}
+
+func (p swaggerParameter) name() string {
+	return scalarValue(mappingValue(p.node, "name"))
+}
+
+func (c *swaggerConverter) warn(pointer, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+		Pointer:  pointer,
+	})
+}
+
+func (c *swaggerConverter) convert() *yaml.Node {
+	result := mappingNode()
+	setMappingValue(result, "openapi", stringNode("3.0.3"))
+	components := mappingNode()
+	for i := 0; i+1 < len(c.document.Content); i += 2 {
+		key, value := c.document.Content[i].Value, c.document.Content[i+1]
+		switch key {
+		case "swagger", "host", "basePath", "schemes", "consumes", "produces":
+		case "info":
+			setMappingValue(result, key, value)
+			if servers := c.servers(); servers != nil {
+				setMappingValue(result, "servers", servers)
+			}
+		case "paths":
+			setMappingValue(result, key, c.paths(value))
+		case "definitions":
+			for j := 1; j < len(value.Content); j += 2 {
+				convertSwaggerSchema(value.Content[j])
+			}
+			setMappingValue(components, "schemas", value)
+		case "parameters":
+			// The body and form data parameters are part of the request bodies
+			// of the operations that refer to them.
+			parameters := mappingNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name, parameter := value.Content[j].Value, value.Content[j+1]
+				in := scalarValue(mappingValue(parameter, "in"))
+				if in != "body" && in != "formData" {
+					setMappingValue(parameters, name, c.parameter("#/parameters/"+EscapeJSONPointer(name), parameter))
+				}
+			}
+			if len(parameters.Content) > 0 {
+				setMappingValue(components, "parameters", parameters)
+			}
+		case "responses":
+			responses := mappingNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name, response := value.Content[j].Value, value.Content[j+1]
+				setMappingValue(responses, name, c.response("#/responses/"+EscapeJSONPointer(name), response, c.produces))
+			}
+			setMappingValue(components, "responses", responses)
+		case "securityDefinitions":
+			schemes := mappingNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name, definition := value.Content[j].Value, value.Content[j+1]
+				setMappingValue(schemes, name, c.securityScheme("#/securityDefinitions/"+EscapeJSONPointer(name), definition))
+			}
+			setMappingValue(components, "securitySchemes", schemes)
+		default:
+			// Like the tags, the security requirements and the extensions.
+			setMappingValue(result, key, value)
+		}
+	}
+	if len(components.Content) > 0 {
+		setMappingValue(result, "components", components)
+	}
+	return result
+}
+
+// The servers of the host, the base path and the schemes of the
+// specification, or nil when it has neither a host nor a base path.
+func (c *swaggerConverter) servers() *yaml.Node {
+	host := scalarValue(mappingValue(c.document, "host"))
+	basePath := scalarValue(mappingValue(c.document, "basePath"))
+	if host == "" && basePath == "" {
+		return nil
+	}
+	urls := []string{basePath}
+	if host != "" {
+		schemes := stringValues(mappingValue(c.document, "schemes"))
+		if len(schemes) == 0 {
+			schemes = []string{"https"}
+		}
+		urls = nil
+		for _, scheme := range schemes {
+			urls = append(urls, scheme+"://"+host+basePath)
+		}
+	}
+	servers := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, url := range urls {
+		server := mappingNode()
+		setMappingValue(server, "url", stringNode(url))
+		servers.Content = append(servers.Content, server)
+	}
+	return servers
+}
+
+func (c *swaggerConverter) paths(paths *yaml.Node) *yaml.Node {
+	result := mappingNode()
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, pathItem := paths.Content[i].Value, paths.Content[i+1]
+		if strings.HasPrefix(path, "x-") {
+			setMappingValue(result, path, pathItem)
+			continue
+		}
+		setMappingValue(result, path, c.pathItem("#/paths/"+EscapeJSONPointer(path), pathItem))
+	}
+	return result
+}
+
+func (c *swaggerConverter) pathItem(pointer string, pathItem *yaml.Node) *yaml.Node {
+	parameters, bodyParameters := c.parameters(pointer+"/parameters", mappingValue(pathItem, "parameters"))
+	result := mappingNode()
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		key, value := pathItem.Content[i].Value, pathItem.Content[i+1]
+		switch {
+		case key == "parameters":
+			if len(parameters.Content) > 0 {
+				setMappingValue(result, key, parameters)
+			}
+		case slices.Contains(swaggerMethods, key):
+			setMappingValue(result, key, c.operation(pointer+"/"+key, value, bodyParameters))
+		default:
+			setMappingValue(result, key, value)
+		}
+	}
+	return result
+}
+
+func (c *swaggerConverter) operation(pointer string, operation *yaml.Node, pathBodyParameters []swaggerParameter) *yaml.Node {
+	parameters, bodyParameters := c.parameters(pointer+"/parameters", mappingValue(operation, "parameters"))
+	// The parameters of the operation override the ones of the path item with
+	// the same name.
+	for _, parameter := range pathBodyParameters {
+		overridden := slices.ContainsFunc(bodyParameters, func(p swaggerParameter) bool {
+			return p.in() == parameter.in() && p.name() == parameter.name()
+		})
+		if !overridden {
+			bodyParameters = append(bodyParameters, parameter)
+		}
+	}
+	consumes, produces := c.consumes, c.produces
+	if value := mappingValue(operation, "consumes"); value != nil {
+		consumes = stringValues(value)
+	}
+	if value := mappingValue(operation, "produces"); value != nil {
+		produces = stringValues(value)
+	}
+	var requestBody *yaml.Node
+	if len(bodyParameters) > 0 {
+		requestBody = c.requestBody(pointer, bodyParameters, consumes)
+	}
+
+	result := mappingNode()
+	for i := 0; i+1 < len(operation.Content); i += 2 {
+		key, value := operation.Content[i].Value, operation.Content[i+1]
+		switch key {
+		case "consumes", "produces":
+		case "schemes":
+			c.warn(pointer+"/schemes", "schemes of an operation cannot be converted, the servers of the specification are used")
+		case "parameters":
+			if len(parameters.Content) > 0 {
+				setMappingValue(result, key, parameters)
+			}
+		case "responses":
+			if requestBody != nil {
+				setMappingValue(result, "requestBody", requestBody)
+				requestBody = nil
+			}
+			setMappingValue(result, key, c.responses(pointer+"/responses", value, produces))
+		default:
+			setMappingValue(result, key, value)
+		}
+	}
+	if requestBody != nil {
+		setMappingValue(result, "requestBody", requestBody)
+	}
+	return result
+}
+
+// Converts the parameters that are not in the body or the form data, and
+// returns the ones that are apart. References to the parameters of the
+// specification are resolved for the ones that are.
+func (c *swaggerConverter) parameters(pointer string, parameters *yaml.Node) (*yaml.Node, []swaggerParameter) {
+	result := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	if parameters == nil {
+		return result, nil
+	}
+	var bodyParameters []swaggerParameter
+	for i, parameter := range parameters.Content {
+		parameterPointer := pointer + "/" + strconv.Itoa(i)
+		resolved := parameter
+		if reference := scalarValue(mappingValue(parameter, "$ref")); reference != "" {
+			resolved = nil
+			if strings.HasPrefix(reference, "#/parameters/") {
+				resolved = resolvePointer(c.document, strings.TrimPrefix(reference, "#"))
+				parameterPointer = reference
+			}
+		}
+		switch in := scalarValue(mappingValue(resolved, "in")); {
+		case in == "body" || in == "formData":
+			bodyParameters = append(bodyParameters, swaggerParameter{pointer: parameterPointer, node: resolved})
+		case resolved != parameter:
+			// A reference, to a parameter of the components.
+			result.Content = append(result.Content, parameter)
+		default:
+			result.Content = append(result.Content, c.parameter(parameterPointer, parameter))
+		}
+	}
+	return result, bodyParameters
+}
+
+// Converts a parameter that is not in the body or the form data, with the
+// keywords of its type moved to its schema.
+func (c *swaggerConverter) parameter(pointer string, parameter *yaml.Node) *yaml.Node {
+	result := mappingNode()
+	for i := 0; i+1 < len(parameter.Content); i += 2 {
+		key, value := parameter.Content[i].Value, parameter.Content[i+1]
+		if !slices.Contains(swaggerTypeKeywords, key) && key != "collectionFormat" {
+			setMappingValue(result, key, value)
+		}
+	}
+	in := scalarValue(mappingValue(parameter, "in"))
+	if style, explode, ok := c.collectionFormat(pointer, in, parameter); ok {
+		setMappingValue(result, "style", stringNode(style))
+		setMappingValue(result, "explode", boolNode(explode))
+	}
+	setMappingValue(result, "schema", c.typeSchema(pointer, parameter))
+	return result
+}
+
+// The style and explode of an array parameter or header in a location, from
+// its collection format, unless they are the defaults of the location.
+func (c *swaggerConverter) collectionFormat(pointer, in string, node *yaml.Node) (string, bool, bool) {
+	if scalarValue(mappingValue(node, "type")) != "array" {
+		return "", false, false
+	}
+	format := scalarValue(mappingValue(node, "collectionFormat"))
+	if format == "" {
+		format = "csv"
+	}
+	query := in == "query" || in == "formData"
+	switch {
+	case format == "multi" && query, format == "csv" && !query:
+		return "", false, false
+	case format == "csv" && query:
+		return "form", false, true
+	case format == "ssv" && query:
+		return "spaceDelimited", false, true
+	case format == "pipes" && query:
+		return "pipeDelimited", false, true
+	}
+	c.warn(pointer+"/collectionFormat", "collectionFormat %s of a %s parameter cannot be converted", format, in)
+	return "", false, false
+}
+
+// The schema of the keywords of the type of a parameter or a header.
+func (c *swaggerConverter) typeSchema(pointer string, node *yaml.Node) *yaml.Node {
+	schema := mappingNode()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch {
+		case key == "items":
+			if format := scalarValue(mappingValue(value, "collectionFormat")); format != "" && format != "csv" {
+				c.warn(pointer+"/items/collectionFormat", "collectionFormat %s of nested arrays cannot be converted", format)
+			}
+			setMappingValue(schema, key, c.typeSchema(pointer+"/items", value))
+		case key == "type" && value.Value == "file":
+			setMappingValue(schema, "type", stringNode("string"))
+			setMappingValue(schema, "format", stringNode("binary"))
+		case slices.Contains(swaggerTypeKeywords, key):
+			setMappingValue(schema, key, value)
+		}
+	}
+	return schema
+}
+
+// The request body of the body parameter of an operation, or of its form data
+// parameters, in the media types that it consumes.
+func (c *swaggerConverter) requestBody(pointer string, parameters []swaggerParameter, consumes []string) *yaml.Node {
+	var body *swaggerParameter
+	var form []swaggerParameter
+	for i, parameter := range parameters {
+		switch {
+		case parameter.in() == "formData":
+			form = append(form, parameter)
+		case body == nil:
+			body = &parameters[i]
+		default:
+			c.warn(parameter.pointer, "body parameter %s is not converted, as the operation has another one", parameter.name())
+		}
+	}
+	if body != nil && len(form) > 0 {
+		c.warn(pointer+"/parameters", "form data parameters are not converted, as the operation has a body parameter")
+		form = nil
+	}
+
+	result := mappingNode()
+	content := mappingNode()
+	if body != nil {
+		if description := mappingValue(body.node, "description"); description != nil {
+			setMappingValue(result, "description", description)
+		}
+		setMappingValue(result, "content", content)
+		if required := mappingValue(body.node, "required"); required != nil {
+			setMappingValue(result, "required", required)
+		}
+		schema := convertSwaggerSchema(mappingValue(body.node, "schema"))
+		if schema == nil {
+			schema = mappingNode()
+		}
+		if len(consumes) == 0 {
+			consumes = []string{"application/json"}
+		}
+		for _, mediaType := range consumes {
+			setMappingValue(content, mediaType, mediaTypeNode(schema))
+		}
+		return result
+	}
+
+	// The form data parameters are the properties of an object.
+	schema := mappingNode()
+	properties := mappingNode()
+	encoding := mappingNode()
+	required := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	file := false
+	for _, parameter := range form {
+		property := c.typeSchema(parameter.pointer, parameter.node)
+		if description := mappingValue(parameter.node, "description"); description != nil {
+			setMappingValue(property, "description", description)
+		}
+		setMappingValue(properties, parameter.name(), property)
+		if scalarValue(mappingValue(parameter.node, "required")) == "true" {
+			required.Content = append(required.Content, stringNode(parameter.name()))
+		}
+		file = file || scalarValue(mappingValue(parameter.node, "type")) == "file"
+		if style, explode, ok := c.collectionFormat(parameter.pointer, "formData", parameter.node); ok {
+			propertyEncoding := mappingNode()
+			setMappingValue(propertyEncoding, "style", stringNode(style))
+			setMappingValue(propertyEncoding, "explode", boolNode(explode))
+			setMappingValue(encoding, parameter.name(), propertyEncoding)
+		}
+	}
+	setMappingValue(schema, "type", stringNode("object"))
+	setMappingValue(schema, "properties", properties)
+	if len(required.Content) > 0 {
+		setMappingValue(schema, "required", required)
+	}
+	mediaTypes := slices.DeleteFunc(slices.Clone(consumes), func(mediaType string) bool {
+		return mediaType != "multipart/form-data" && mediaType != "application/x-www-form-urlencoded"
+	})
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/x-www-form-urlencoded"}
+		if file {
+			mediaTypes = []string{"multipart/form-data"}
+		}
+	}
+	setMappingValue(result, "content", content)
+	for _, mediaType := range mediaTypes {
+		node := mediaTypeNode(schema)
+		if len(encoding.Content) > 0 {
+			setMappingValue(node, "encoding", encoding)
+		}
+		setMappingValue(content, mediaType, node)
+	}
+	if len(required.Content) > 0 {
+		setMappingValue(result, "required", boolNode(true))
+	}
+	return result
+}
+
+func (c *swaggerConverter) responses(pointer string, responses *yaml.Node, produces []string) *yaml.Node {
+	result := mappingNode()
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		code, response := responses.Content[i].Value, responses.Content[i+1]
+		if strings.HasPrefix(code, "x-") || mappingValue(response, "$ref") != nil {
+			setMappingValue(result, code, response)
+			continue
+		}
+		setMappingValue(result, code, c.response(pointer+"/"+EscapeJSONPointer(code), response, produces))
+	}
+	return result
+}
+
+// Converts a response, with its schema in the media types that its operation
+// produces, and its examples in the ones that it has.
+func (c *swaggerConverter) response(pointer string, response *yaml.Node, produces []string) *yaml.Node {
+	result := mappingNode()
+	schema := mappingValue(response, "schema")
+	examples := mappingValue(response, "examples")
+	for i := 0; i+1 < len(response.Content); i += 2 {
+		key, value := response.Content[i].Value, response.Content[i+1]
+		switch key {
+		case "schema", "examples":
+		case "headers":
+			headers := mappingNode()
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				name, header := value.Content[j].Value, value.Content[j+1]
+				setMappingValue(headers, name, c.header(pointer+"/headers/"+EscapeJSONPointer(name), header))
+			}
+			setMappingValue(result, key, headers)
+		default:
+			setMappingValue(result, key, value)
+		}
+	}
+	var mediaTypes []string
+	if schema != nil {
+		schema = convertSwaggerSchema(schema)
+		mediaTypes = produces
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{"application/json"}
+		}
+		content := mappingNode()
+		for _, mediaType := range mediaTypes {
+			node := mediaTypeNode(schema)
+			if example := mappingValue(examples, mediaType); example != nil {
+				setMappingValue(node, "example", example)
+			}
+			setMappingValue(content, mediaType, node)
+		}
+		setMappingValue(result, "content", content)
+	}
+	if examples != nil {
+		for i := 0; i+1 < len(examples.Content); i += 2 {
+			if mediaType := examples.Content[i].Value; !slices.Contains(mediaTypes, mediaType) {
+				c.warn(pointer+"/examples/"+EscapeJSONPointer(mediaType),
+					"example of media type %s is not converted, as the response has no content of that type", mediaType)
+			}
+		}
+	}
+	return result
+}
+
+// Converts a response header, with the keywords of its type moved to its
+// schema.
+func (c *swaggerConverter) header(pointer string, header *yaml.Node) *yaml.Node {
+	result := mappingNode()
+	for i := 0; i+1 < len(header.Content); i += 2 {
+		key, value := header.Content[i].Value, header.Content[i+1]
+		if !slices.Contains(swaggerTypeKeywords, key) && key != "collectionFormat" {
+			setMappingValue(result, key, value)
+		}
+	}
+	c.collectionFormat(pointer, "header", header)
+	setMappingValue(result, "schema", c.typeSchema(pointer, header))
+	return result
+}
+
+// Converts a security definition, of which the basic and the OAuth2 ones
+// changed in OpenAPI 3.
+func (c *swaggerConverter) securityScheme(pointer string, definition *yaml.Node) *yaml.Node {
+	kind := scalarValue(mappingValue(definition, "type"))
+	if kind != "basic" && kind != "oauth2" {
+		return definition
+	}
+	result := mappingNode()
+	flow := mappingNode()
+	for i := 0; i+1 < len(definition.Content); i += 2 {
+		key, value := definition.Content[i].Value, definition.Content[i+1]
+		switch {
+		case key == "type" && kind == "basic":
+			setMappingValue(result, "type", stringNode("http"))
+			setMappingValue(result, "scheme", stringNode("basic"))
+		case key == "flow":
+		case key == "authorizationUrl", key == "tokenUrl", key == "scopes":
+			setMappingValue(flow, key, value)
+		default:
+			setMappingValue(result, key, value)
+		}
+	}
+	if kind == "oauth2" {
+		name, ok := swaggerOAuthFlows[scalarValue(mappingValue(definition, "flow"))]
+		if !ok {
+			c.warn(pointer+"/flow", "unknown OAuth2 flow %q", scalarValue(mappingValue(definition, "flow")))
+			return result
+		}
+		if mappingValue(flow, "scopes") == nil {
+			setMappingValue(flow, "scopes", mappingNode())
+		}
+		flows := mappingNode()
+		setMappingValue(flows, name, flow)
+		setMappingValue(result, "flows", flows)
+	}
+	return result
+}
+
+// Converts the keywords of a Swagger 2.0 schema, and of its subschemas, that
+// changed in OpenAPI 3.0. The schema is modified and returned.
+func convertSwaggerSchema(schema *yaml.Node) *yaml.Node {
+	if schema == nil || schema.Kind != yaml.MappingNode {
+		return schema
+	}
+	file := false
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i], schema.Content[i+1]
+		switch key.Value {
+		case "type":
+			if value.Value == "file" {
+				value.Value, file = "string", true
+			}
+		case "discriminator":
+			// The name of the property, which is an object in OpenAPI 3.
+			if value.Kind == yaml.ScalarNode {
+				discriminator := mappingNode()
+				setMappingValue(discriminator, "propertyName", value)
+				schema.Content[i+1] = discriminator
+			}
+		case "x-nullable":
+			key.Value = "nullable"
+		case "properties":
+			for j := 1; j < len(value.Content); j += 2 {
+				convertSwaggerSchema(value.Content[j])
+			}
+		case "items", "additionalProperties":
+			convertSwaggerSchema(value)
+		case "allOf":
+			for _, subschema := range value.Content {
+				convertSwaggerSchema(subschema)
+			}
+		}
+	}
+	if file && mappingValue(schema, "format") == nil {
+		setMappingValue(schema, "format", stringNode("binary"))
+	}
+	return schema
+}
+
+// Points the local references of Swagger 2.0, like "#/definitions/Pet", to
+// the components of OpenAPI 3, like "#/components/schemas/Pet".
+func rewriteSwaggerReferences(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value != "$ref" || value.Kind != yaml.ScalarNode {
+				continue
+			}
+			for _, prefixes := range swaggerPointerPrefixes {
+				if strings.HasPrefix(value.Value, prefixes[0]) {
+					value.Value = prefixes[1] + strings.TrimPrefix(value.Value, prefixes[0])
+				}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		rewriteSwaggerReferences(child)
+	}
+}
+
+func mediaTypeNode(schema *yaml.Node) *yaml.Node {
+	node := mappingNode()
+	setMappingValue(node, "schema", schema)
+	return node
+}
+
+func mappingNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func boolNode(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value)}
+}
+
+// The value of a scalar node, or an empty string for other nodes and nil.
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// The values of a sequence of scalars, like the media types of consumes.
+func stringValues(node *yaml.Node) []string {
+	if node == nil {
+		return nil
+	}
+	var values []string
+	for _, child := range node.Content {
+		if value := scalarValue(child); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConvertSwagger(t *testing.T) {
+	tests := map[string]struct {
+		spec     string
+		expected string
+		warnings []string
+	}{
+		"servers": {
+			spec: `
+swagger: "2.0"
+info: {title: Pets, version: 1.0.0}
+host: pets.example.com
+basePath: /v1
+schemes: [https, http]
+paths: {}`,
+			expected: `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+servers:
+  - url: https://pets.example.com/v1
+  - url: http://pets.example.com/v1
+paths: {}`,
+		},
+		"body parameter": {
+			spec: `
+swagger: "2.0"
+consumes: [application/json]
+paths:
+  /pets:
+    post:
+      operationId: add-pet
+      parameters:
+        - {name: pet, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+        - {name: dry-run, in: query, type: boolean}
+      responses:
+        "201": {description: Created, schema: {$ref: "#/definitions/Pet"}}
+definitions:
+  Pet: {type: object, properties: {name: {type: string, x-nullable: true}}}`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets:
+    post:
+      operationId: add-pet
+      parameters:
+        - {name: dry-run, in: query, schema: {type: boolean}}
+      requestBody:
+        content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}
+        required: true
+      responses:
+        "201":
+          description: Created
+          content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}
+components:
+  schemas:
+    Pet: {type: object, properties: {name: {type: string, nullable: true}}}`,
+		},
+		"form data parameters": {
+			spec: `
+swagger: "2.0"
+paths:
+  /pets/{id}/photo:
+    parameters:
+      - {name: id, in: path, required: true, type: integer}
+    post:
+      parameters:
+        - {name: photo, in: formData, required: true, type: file}
+        - {name: tags, in: formData, type: array, items: {type: string}}
+      responses: {}`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets/{id}/photo:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                photo: {type: string, format: binary}
+                tags: {type: array, items: {type: string}}
+              required: [photo]
+            encoding: {tags: {style: form, explode: false}}
+        required: true
+      responses: {}`,
+		},
+		"referenced parameters": {
+			spec: `
+swagger: "2.0"
+paths:
+  /pets:
+    put:
+      parameters:
+        - $ref: "#/parameters/limit"
+        - $ref: "#/parameters/pet"
+      responses: {}
+parameters:
+  limit: {name: limit, in: query, type: integer, maximum: 100}
+  pet: {name: pet, in: body, schema: {type: object}}`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets:
+    put:
+      parameters:
+        - $ref: "#/components/parameters/limit"
+      requestBody:
+        content: {application/json: {schema: {type: object}}}
+      responses: {}
+components:
+  parameters:
+    limit: {name: limit, in: query, schema: {type: integer, maximum: 100}}`,
+		},
+		"responses": {
+			spec: `
+swagger: "2.0"
+produces: [application/json, application/xml]
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          headers:
+            X-Rate-Limit: {type: integer, description: Requests left}
+          schema: {type: array, items: {type: string}}
+          examples:
+            application/json: [rex]
+            text/plain: rex
+        default: {$ref: "#/responses/Error"}
+responses:
+  Error: {description: Error}`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          headers:
+            X-Rate-Limit: {description: Requests left, schema: {type: integer}}
+          content:
+            application/json: {schema: {type: array, items: {type: string}}, example: [rex]}
+            application/xml: {schema: {type: array, items: {type: string}}}
+        default: {$ref: "#/components/responses/Error"}
+components:
+  responses:
+    Error: {description: Error}`,
+			warnings: []string{
+				"#/paths/~1pets/get/responses/200/examples/text~1plain: example of media type text/plain is not converted, as the response has no content of that type",
+			},
+		},
+		"collection formats": {
+			spec: `
+swagger: "2.0"
+paths:
+  /pets:
+    get:
+      schemes: [https]
+      parameters:
+        - {name: ids, in: query, type: array, items: {type: integer}, collectionFormat: pipes}
+        - {name: tags, in: query, type: array, items: {type: string}, collectionFormat: multi}
+        - {name: names, in: query, type: array, items: {type: string}, collectionFormat: tsv}
+      responses: {}`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: ids, in: query, style: pipeDelimited, explode: false, schema: {type: array, items: {type: integer}}}
+        - {name: tags, in: query, schema: {type: array, items: {type: string}}}
+        - {name: names, in: query, schema: {type: array, items: {type: string}}}
+      responses: {}`,
+			warnings: []string{
+				"#/paths/~1pets/get/parameters/2/collectionFormat: collectionFormat tsv of a query parameter cannot be converted",
+				"#/paths/~1pets/get/schemes: schemes of an operation cannot be converted, the servers of the specification are used",
+			},
+		},
+		"security definitions": {
+			spec: `
+swagger: "2.0"
+paths: {}
+securityDefinitions:
+  basic: {type: basic}
+  key: {type: apiKey, name: key, in: header}
+  oauth: {type: oauth2, flow: accessCode, authorizationUrl: https://example.com/auth, tokenUrl: https://example.com/token, scopes: {read: Read}}`,
+			expected: `
+openapi: 3.0.3
+paths: {}
+components:
+  securitySchemes:
+    basic: {type: http, scheme: basic}
+    key: {type: apiKey, name: key, in: header}
+    oauth:
+      type: oauth2
+      flows:
+        authorizationCode: {authorizationUrl: https://example.com/auth, tokenUrl: https://example.com/token, scopes: {read: Read}}`,
+		},
+		"discriminator": {
+			spec: `
+swagger: "2.0"
+paths: {}
+definitions:
+  Pet: {type: object, discriminator: kind, properties: {kind: {type: string}}}`,
+			expected: `
+openapi: 3.0.3
+paths: {}
+components:
+  schemas:
+    Pet: {type: object, discriminator: {propertyName: kind}, properties: {kind: {type: string}}}`,
+		},
+		"openapi 3": {
+			spec:     "openapi: 3.1.0\npaths: {}\n",
+			expected: "openapi: 3.1.0\npaths: {}\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			converted, diagnostics, err := ConvertSwagger([]byte(test.spec))
+			require.NoError(t, err)
+
+			var actual, expected any
+			require.NoError(t, yaml.Unmarshal(converted, &actual))
+			require.NoError(t, yaml.Unmarshal([]byte(test.expected), &expected))
+			assert.Equal(t, expected, actual)
+			var warnings []string
+			for _, diagnostic := range diagnostics {
+				assert.Equal(t, SeverityWarning, diagnostic.Severity)
+				warnings = append(warnings, diagnostic.Error())
+			}
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+}
+
+func TestConvertSwaggerVersion(t *testing.T) {
+	_, _, err := ConvertSwagger([]byte(`swagger: "1.2"`))
+	assert.ErrorContains(t, err, "unsupported Swagger version 1.2")
+}
+
+func TestSwaggerPointer(t *testing.T) {
+	tests := map[string]string{
+		"#/components/schemas/Pet/properties/name":                          "#/definitions/Pet/properties/name",
+		"#/components/responses/Error/content/application~1json/schema":     "#/responses/Error/schema",
+		"#/paths/~1pets/get/responses/200/content/application~1json/schema": "#/paths/~1pets/get/responses/200/schema",
+		"#/paths/~1pets/post/requestBody/content/application~1json/schema":  "#/paths/~1pets/post/parameters",
+		"#/paths/~1pets/get/parameters/1/schema":                            "#/paths/~1pets/get/parameters",
+		"#/paths/~1pets/get/operationId":                                    "#/paths/~1pets/get/operationId",
+	}
+	for pointer, expected := range tests {
+		t.Run(pointer, func(t *testing.T) {
+			assert.Equal(t, expected, swaggerPointer(pointer))
+		})
+	}
+}
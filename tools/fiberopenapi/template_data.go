@@ -0,0 +1,168 @@
+package main
+
+import "slices"
+
+// The data that the templates of the handlers and of the client are executed
+// with: handlers.tmpl, operations.tmpl, specification.tmpl and client.tmpl.
+// The templates of each operation, handler.tmpl and client_operation.tmpl,
+// are executed with an OperationData instead, and model.tmpl with the
+// ModelData of each model.
+type TemplateData struct {
+	// The name of the handlers interface, or of the client, like Handlers.
+	TypeName   string
+	Operations []OperationData
+	// The interfaces of the tags that the handlers interface embeds, when
+	// there is one for each tag.
+	Interfaces []InterfaceData
+	// Whether the specification is embedded in the package, to serve it with
+	// the handlers, and the names of its files next to the handlers.
+	EmbedSpec         bool
+	SpecJSONFile      string
+	SpecYAMLFile      string
+	SpecReferenceFile string
+}
+
+// The interface of the operations of a tag, like GameplayHandlers. Operations
+// with many tags are in the interface of their first one.
+type InterfaceData struct {
+	Name       string
+	Tag        string
+	Operations []OperationData
+}
+
+// The operations that are not in the interface of a tag.
+func (d TemplateData) UntaggedOperations() []OperationData {
+	if len(d.Interfaces) == 0 {
+		return d.Operations
+	}
+	var operations []OperationData
+	for _, operation := range d.Operations {
+		if len(operation.Tags) == 0 {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// An operation, with its parameters and its responses, in the templates.
+type OperationData struct {
+	Operation
+	// The name of the handlers interface, or of the client.
+	TypeName string
+	// The position of the operation in the operations table.
+	Index int
+}
+
+// Whether any of the parameters of the operation is in a location, like query.
+func (o OperationData) HasParametersIn(in string) bool {
+	for _, parameter := range o.Parameters {
+		if parameter.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+func newTemplateData(typeName string, operations []Operation) TemplateData {
+	data := TemplateData{TypeName: typeName}
+	for i, operation := range operations {
+		data.Operations = append(data.Operations, OperationData{
+			Operation: operation,
+			TypeName:  typeName,
+			Index:     i,
+		})
+	}
+	return data
+}
+
+// The interfaces of the tags of the operations, in the order of their first
+// operation. Tags with the same Go name share their interface.
+func tagInterfaces(typeName string, operations []OperationData) []InterfaceData {
+	var interfaces []InterfaceData
+	positions := map[string]int{}
+	for _, operation := range operations {
+		if len(operation.Tags) == 0 {
+			continue
+		}
+		tag := operation.Tags[0]
+		name := ToPascalCase(tag) + typeName
+		i, ok := positions[name]
+		if !ok {
+			i = len(interfaces)
+			positions[name] = i
+			interfaces = append(interfaces, InterfaceData{Name: name, Tag: tag})
+		}
+		interfaces[i].Operations = append(interfaces[i].Operations, operation)
+	}
+	return interfaces
+}
+
+// A model in model.tmpl. Its ModelType has the Name, Docstring, Definition and
+// Methods of the model, written as Go code. The structure that they are
+// written from is here too, for templates to write them differently, like
+// with other struct tags.
+type ModelData struct {
+	ModelType
+	// The fields of the struct of an object model, in the order of its
+	// properties. Other models have none.
+	Fields []FieldData
+	// The Go statements of UnmarshalJSON that check the keywords that are
+	// validated against the JSON value, like const or if, or empty. They
+	// check v, the RawValue of the data being decoded, and append to errs.
+	Checks string
+}
+
+// A field of the struct of an object model.
+type FieldData struct {
+	// The Go name and type of the field, which is a pointer when the property
+	// is optional, like *Tag.
+	Name string
+	Type string
+	// The name of the property, and the value of the json struct tag of the
+	// field, like tag,omitempty.
+	Property string
+	JSONTag  string
+	// The doc comment of the field, indented with a tab, or empty.
+	Docstring string
+	Required  bool
+	ReadOnly  bool
+	WriteOnly bool
+	// The option of the constructor that sets an optional property, like
+	// WithPetTag, and its default value in JSON, if it has one.
+	Option  string
+	Default string
+}
+
+func newModelData(modelType ModelType) ModelData {
+	data := ModelData{ModelType: modelType}
+	if m, ok := modelType.(hasBase); ok {
+		data.Checks = m.base().checks
+	}
+	m, ok := modelType.(*objectModel)
+	if !ok {
+		return data
+	}
+	for pair := m.properties.First(); pair != nil; pair = pair.Next() {
+		key, schema := pair.Key(), pair.Value().Schema()
+		field := FieldData{
+			Name:      m.fieldName(key),
+			Type:      m.fieldType(key),
+			Property:  key,
+			JSONTag:   m.jsonTag(key),
+			Docstring: docComment("\t", schemaDoc(schema)...),
+			Required:  slices.Contains(m.schema.Required, key),
+			ReadOnly:  isReadOnly(schema),
+			WriteOnly: isWriteOnly(schema),
+		}
+		if !field.Required {
+			field.Option = "With" + m.name + field.Name
+			if schema.Default != nil {
+				if value, err := jsonValue(schema.Default); err == nil {
+					field.Default = jsonText(value)
+				}
+			}
+		}
+		data.Fields = append(data.Fields, field)
+	}
+	return data
+}
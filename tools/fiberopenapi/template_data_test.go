@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagInterfaces(t *testing.T) {
+	data := newTemplateData("Handlers", []Operation{
+		{ID: "get-board", Tags: []string{"Gameplay"}},
+		{ID: "get-health"},
+		{ID: "list-players", Tags: []string{"players", "Gameplay"}},
+		{ID: "put-square", Tags: []string{"Gameplay"}},
+		{ID: "add-player", Tags: []string{"Players"}},
+	})
+	data.Interfaces = tagInterfaces(data.TypeName, data.Operations)
+
+	ids := func(operations []OperationData) []string {
+		var ids []string
+		for _, operation := range operations {
+			ids = append(ids, operation.ID)
+		}
+		return ids
+	}
+	var names, tags []string
+	var operations [][]string
+	for _, tagInterface := range data.Interfaces {
+		names = append(names, tagInterface.Name)
+		tags = append(tags, tagInterface.Tag)
+		operations = append(operations, ids(tagInterface.Operations))
+	}
+	// Tags with the same Go name share the interface of the first one.
+	assert.Equal(t, []string{"GameplayHandlers", "PlayersHandlers"}, names)
+	assert.Equal(t, []string{"Gameplay", "players"}, tags)
+	assert.Equal(t, [][]string{{"get-board", "put-square"}, {"list-players", "add-player"}}, operations)
+	assert.Equal(t, []string{"get-health"}, ids(data.UntaggedOperations()))
+
+	data.Interfaces = nil
+	assert.Len(t, data.UntaggedOperations(), 5)
+}
+
+func TestModelData(t *testing.T) {
+	spec, err := loadOpenAPIDocument([]byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Models", "version": "1.0.0"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"pet": {
+					"type": "object",
+					"required": ["name"],
+					"const": {"name": "Rex"},
+					"properties": {
+						"name": {"type": "string", "description": "The name."},
+						"status": {"type": "string", "default": "available", "readOnly": true}
+					}
+				}
+			}
+		}
+	}`), ".")
+	require.NoError(t, err)
+	modelTypes, diagnostics := ExtractModelTypesFromDocument(spec, ModelOptions{})
+	require.Empty(t, diagnostics)
+
+	data := newModelData(modelTypes[0])
+	assert.Equal(t, []FieldData{{
+		Name:      "Name",
+		Type:      "Name",
+		Property:  "name",
+		JSONTag:   "name",
+		Docstring: "\t// The name.\n",
+		Required:  true,
+	}, {
+		Name:     "Status",
+		Type:     "*Status",
+		Property: "status",
+		JSONTag:  "status,omitempty",
+		ReadOnly: true,
+		Option:   "WithPetStatus",
+		Default:  `"available"`,
+	}}, data.Fields)
+	assert.Contains(t, data.Checks, "NewConstError")
+	assert.Empty(t, newModelData(modelTypes[1]).Fields)
+
+	// A template that writes the structs with other struct tags.
+	model, err := template.New("model.tmpl").Parse(`
+{{- if .Fields}}type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSONTag}}" db:"{{.Property}}"` + "`" + `
+{{- end}}
+}
+{{- end}}`)
+	require.NoError(t, err)
+	var result strings.Builder
+	require.NoError(t, model.Execute(&result, data))
+	assert.Equal(t, "type Pet struct {\n"+
+		"\tName Name `json:\"name\" db:\"name\"`\n"+
+		"\tStatus *Status `json:\"status,omitempty\" db:\"status\"`\n"+
+		"}", result.String())
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// The templates of the generated code, executed with the data of
+// template_data.go.
+//
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// The functions that the templates can use besides the ones of text/template.
+// The import function, like {{import "log/slog"}}, registers imports in the
+// generated file, see Generator.Execute.
+var templateFuncs = template.FuncMap{
+	"import":          func(specs ...string) string { return "" },
+	"quote":           strconv.Quote,
+	"upper":           strings.ToUpper,
+	"camel":           ToCamelCase,
+	"pascal":          ToPascalCase,
+	"docComment":      docComment,
+	"operationDoc":    operationDoc,
+	"stringsLiteral":  stringsLiteral,
+	"securityLiteral": securityLiteral,
+	"routePattern":    routePattern,
+}
+
+// Loads the templates of the generated code. The ones in dir, if it is not
+// empty, replace the embedded templates with the same file name, like
+// handler.tmpl, so that the generated code can be tweaked without forking
+// the generator.
+func LoadTemplates(dir string) (*template.Template, error) {
+	templates, err := template.New("").Funcs(templateFuncs).ParseFS(embeddedTemplates, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse embedded templates: %w", err)
+	}
+	if dir == "" {
+		return templates, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list templates in %s: %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no templates in %s", dir)
+	}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if templates.Lookup(name) == nil {
+			return nil, fmt.Errorf("template %s does not replace any of the embedded ones", path)
+		}
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read template: %w", err)
+		}
+		if _, err := templates.New(name).Parse(string(text)); err != nil {
+			return nil, fmt.Errorf("cannot parse template: %w", err)
+		}
+	}
+	return templates, nil
+}
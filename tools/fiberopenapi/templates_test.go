@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	model := fixedModelType{name: "Name", definition: "string"}
+	tests := map[string]struct {
+		templates map[string]string
+		expected  string
+		err       string
+	}{
+		"embedded": {
+			expected: "\ntype Name string\n",
+		},
+		"override": {
+			templates: map[string]string{
+				"model.tmpl": "\n// {{.Name}} is a model.\ntype {{.Name}} {{.Definition}}\n",
+			},
+			expected: "\n// Name is a model.\ntype Name string\n",
+		},
+		"unknown template": {
+			templates: map[string]string{"handlres.tmpl": ""},
+			err:       "handlres.tmpl does not replace any of the embedded ones",
+		},
+		"invalid template": {
+			templates: map[string]string{"model.tmpl": "{{.Name"},
+			err:       "cannot parse template",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var dir string
+			if test.templates != nil {
+				dir = t.TempDir()
+				for name, text := range test.templates {
+					require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644))
+				}
+			}
+			templates, err := LoadTemplates(dir)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			var result strings.Builder
+			require.NoError(t, templates.ExecuteTemplate(&result, "model.tmpl", model))
+			assert.Equal(t, test.expected, strings.TrimRight(result.String(), "\n")+"\n")
+		})
+	}
+}
+
+func TestHandlerDeprecatedParameters(t *testing.T) {
+	templates, err := LoadTemplates("")
+	require.NoError(t, err)
+	operation := OperationData{
+		Operation: Operation{
+			Name: "ListPets",
+			Parameters: []Parameter{
+				{Name: "id", Type: "Id", ParamName: "id", In: "path", Deprecated: true},
+				{Name: "limit", Type: "Limit", ParamName: "limit", In: "query", Deprecated: true},
+				{Name: "trace", Type: "Trace", ParamName: "X-Trace", In: "header", Deprecated: true},
+				{Name: "session", Type: "Session", ParamName: "session", In: "cookie", Deprecated: true},
+				{Name: "sort", Type: "Sort", ParamName: "sort", In: "query"},
+			},
+		},
+		TypeName: "Handlers",
+	}
+	var result strings.Builder
+	require.NoError(t, templates.ExecuteTemplate(&result, "handler.tmpl", operation))
+
+	// Each deprecated parameter is read from where it is sent.
+	for _, value := range []string{`c.Params("id")`, `c.Query("limit")`, `c.Get("X-Trace")`, `c.Cookies("session")`} {
+		assert.Contains(t, result.String(), "if h.onDeprecatedParameter != nil && "+value+` != "" {`)
+	}
+	assert.NotContains(t, result.String(), `h.onDeprecatedParameter(c, "ListPets", "sort")`)
+}
+
+// A model type with a fixed definition and without methods.
+type fixedModelType struct {
+	name       string
+	definition string
+}
+
+func (m fixedModelType) Name() string       { return m.name }
+func (m fixedModelType) Docstring() string  { return "" }
+func (m fixedModelType) Definition() string { return m.definition }
+func (m fixedModelType) Methods() string    { return "" }
+func (m fixedModelType) Imports() []string  { return nil }
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Converts a string from kebab-case, camelCase, PascalCase or with spaces to
+// snake_case, like the names of Go files.
+func ToSnakeCase(s string) string {
+	// Replace all non-alphanumeric characters with underscores
+	nonAlphanumericPattern := regexp.MustCompile(`[^a-zA-Z0-9]+`)
+	s = nonAlphanumericPattern.ReplaceAllString(s, "_")
+
+	// Handle camelCase by inserting underscores before capital letters that
+	// follow lower case letters or digits
+	camelCasePattern := regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	s = camelCasePattern.ReplaceAllString(s, "${1}_${2}")
+
+	return strings.ToLower(strings.Trim(s, "_"))
+}
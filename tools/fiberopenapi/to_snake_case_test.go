@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"kebab-case to snake_case": {
+			input:    "hello-world",
+			expected: "hello_world",
+		},
+		"camelCase to snake_case": {
+			input:    "helloWorld",
+			expected: "hello_world",
+		},
+		"PascalCase to snake_case": {
+			input:    "HelloWorld",
+			expected: "hello_world",
+		},
+		"with spaces": {
+			input:    "Pet Store",
+			expected: "pet_store",
+		},
+		"empty string": {
+			input:    "",
+			expected: "",
+		},
+		"multiple consecutive separators": {
+			input:    "hello--world__example",
+			expected: "hello_world_example",
+		},
+		"already in snake_case": {
+			input:    "hello_world",
+			expected: "hello_world",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := ToSnakeCase(tc.input)
+			if result != tc.expected {
+				t.Errorf("ToSnakeCase(%q) = %q, want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+}